@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+	"strconv"
 	"time"
 
 	bc "github.com/BurrowBlocks/blockchain"
@@ -18,9 +20,27 @@ var gConfig *config.Config
 
 func main() {
 
+	//Migrating schema only needs database, so it works while node is down
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		RunMigrate(os.Args[2:])
+		return
+	}
+
 	//Initializing...
 	Init()
 
+	//Running one-shot command...
+	if len(os.Args) > 1 {
+		RunCommand(os.Args[1:])
+		return
+	}
+
+	//Loading contract ABIs...
+	errLoadABIs := explorerEngine.LoadABIs()
+	if errLoadABIs != nil {
+		println("Loading ABIs error: ", errLoadABIs.Error())
+	}
+
 	//Prepairing Restful API...
 	go func() {
 		//defer dbAdapter.Disconnect()
@@ -35,11 +55,66 @@ func main() {
 //Init initializes engine
 func Init() {
 	gConfig, _ = config.LoadConfigFile(true)
+
 	bcAdapter = bc.Burrow{Config: gConfig}
 	dbAdapter = db.Postgre{Config: gConfig}
 	explorerEngine = ex.Explorer{BCAdapter: &bcAdapter, DBAdapter: &dbAdapter, Config: gConfig}
 
-	explorerEngine.Init()
+	initErr := explorerEngine.Init()
+	if initErr != nil {
+		println("Initializing engine error: ", initErr.Error())
+		os.Exit(1)
+	}
+}
+
+//RunCommand runs a one-shot command and exits
+func RunCommand(args []string) {
+	defer dbAdapter.Disconnect()
+
+	switch args[0] {
+	case "backfill":
+		errBackfill := explorerEngine.Backfill()
+		if errBackfill != nil {
+			println("Backfilling error: ", errBackfill.Error())
+			os.Exit(1)
+		}
+	default:
+		println("unknown command:", args[0])
+		println("usage: BurrowBlocks [backfill | migrate [version]]")
+		os.Exit(2)
+	}
+}
+
+//RunMigrate connects to database only and migrates its schema to version, latest version if it is not given
+func RunMigrate(args []string) {
+	gConfig, _ = config.LoadConfigFile(true)
+
+	version := db.LatestSchemaVersion()
+	if len(args) > 0 {
+		v, errVersion := strconv.Atoi(args[0])
+		if errVersion != nil {
+			println("invalid schema version:", args[0])
+			os.Exit(2)
+		}
+		version = v
+	}
+
+	//migrate command decides itself which migrations should be applied
+	gConfig.DataBase.AutoMigrate = false
+	dbAdapter = db.Postgre{Config: gConfig}
+	errConnect := dbAdapter.Connect()
+	if errConnect != nil {
+		println("Connecting to database error: ", errConnect.Error())
+		os.Exit(1)
+	}
+	defer dbAdapter.Disconnect()
+
+	errMigrate := dbAdapter.MigrateTo(version)
+	if errMigrate != nil {
+		println("Migrating error: ", errMigrate.Error())
+		os.Exit(1)
+	}
+	println("database schema is at version", version)
 }
 
 //SyncLoop goes in loop for syncing blockchain and database
@@ -49,6 +124,9 @@ func SyncLoop() {
 	println("syncing every", interval, "miliseconds...")
 	defer dbAdapter.Disconnect()
 
+	gapScanInterval := time.Duration(gConfig.App.GapScanInterval)
+	lastGapScan := time.Now()
+
 	for {
 
 		//go func() {
@@ -57,6 +135,14 @@ func SyncLoop() {
 			println("Updating engine error: ", errUpdate.Error())
 		}
 		//}()
+
+		if gapScanInterval > 0 && time.Since(lastGapScan) >= gapScanInterval*time.Millisecond {
+			errBackfill := explorerEngine.Backfill()
+			if errBackfill != nil {
+				println("Backfilling error: ", errBackfill.Error())
+			}
+			lastGapScan = time.Now()
+		}
 		time.Sleep(interval * time.Millisecond)
 
 	}
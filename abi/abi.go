@@ -0,0 +1,273 @@
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+//Argument defines an input of a function or an event, components are set for tuples
+type Argument struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Indexed    bool       `json:"indexed"`
+	Components []Argument `json:"components"`
+}
+
+//Method defines a function of a contract
+type Method struct {
+	Name      string
+	Signature string
+	Selector  string
+	Inputs    []Argument
+}
+
+//Event defines an event of a contract
+type Event struct {
+	Name      string
+	Signature string
+	Topic     string
+	Inputs    []Argument
+	Anonymous bool
+}
+
+//ABI defines functions and events of a contract, they are keyed by
+//selector and topic in upper case hex
+type ABI struct {
+	Functions map[string]Method
+	Events    map[string]Event
+}
+
+//entry is an item of ABI json
+type entry struct {
+	Type      string     `json:"type"`
+	Name      string     `json:"name"`
+	Inputs    []Argument `json:"inputs"`
+	Anonymous bool       `json:"anonymous"`
+}
+
+//Parse reads ABI json of a contract, constructors, fallbacks and errors are ignored
+func Parse(data []byte) (*ABI, error) {
+	var entries []entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid abi json: %s", err.Error())
+	}
+
+	a := &ABI{
+		Functions: make(map[string]Method),
+		Events:    make(map[string]Event),
+	}
+	for _, e := range entries {
+		if e.Type != "function" && e.Type != "event" && e.Type != "" {
+			continue
+		}
+		if e.Name == "" {
+			return nil, fmt.Errorf("abi %s has no name", e.Type)
+		}
+
+		signature, err := signatureOf(e.Name, e.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		hash := keccak256([]byte(signature))
+
+		if e.Type == "event" {
+			topic := strings.ToUpper(hex.EncodeToString(hash))
+			a.Events[topic] = Event{Name: e.Name, Signature: signature, Topic: topic, Inputs: e.Inputs, Anonymous: e.Anonymous}
+			continue
+		}
+		//type of functions may be omitted in old ABIs
+		selector := strings.ToUpper(hex.EncodeToString(hash[:4]))
+		a.Functions[selector] = Method{Name: e.Name, Signature: signature, Selector: selector, Inputs: e.Inputs}
+	}
+
+	return a, nil
+}
+
+//signatureOf returns canonical signature of a function or an event like transfer(address,uint256)
+func signatureOf(name string, inputs []Argument) (string, error) {
+	types := make([]string, len(inputs))
+	for i, input := range inputs {
+		t, err := parseType(input)
+		if err != nil {
+			return "", fmt.Errorf("invalid type of %s.%s: %s", name, input.Name, err.Error())
+		}
+		types[i] = t.canonical()
+	}
+	return name + "(" + strings.Join(types, ",") + ")", nil
+}
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+const (
+	kindUint = iota
+	kindInt
+	kindAddress
+	kindBool
+	kindFixedBytes
+	kindBytes
+	kindString
+	kindSlice
+	kindArray
+	kindTuple
+)
+
+//abiType is a parsed type of an argument, elem is set for arrays and slices
+//and components for tuples
+type abiType struct {
+	kind       int
+	size       int
+	length     int
+	elem       *abiType
+	components []abiType
+	names      []string
+}
+
+//parseType parses type of an argument, e.g. uint256, bytes32[] or tuple[2]
+func parseType(arg Argument) (abiType, error) {
+	typ := arg.Type
+
+	//arrays are parsed from outermost dimension that is written last
+	if strings.HasSuffix(typ, "]") {
+		open := strings.LastIndex(typ, "[")
+		if open < 0 {
+			return abiType{}, fmt.Errorf("unknown type %s", typ)
+		}
+
+		elem, err := parseType(Argument{Type: typ[:open], Components: arg.Components})
+		if err != nil {
+			return abiType{}, err
+		}
+
+		dim := typ[open+1 : len(typ)-1]
+		if dim == "" {
+			return abiType{kind: kindSlice, elem: &elem}, nil
+		}
+		length, err := strconv.Atoi(dim)
+		if err != nil || length <= 0 {
+			return abiType{}, fmt.Errorf("invalid array length of %s", typ)
+		}
+		return abiType{kind: kindArray, length: length, elem: &elem}, nil
+	}
+
+	switch {
+	case typ == "address":
+		return abiType{kind: kindAddress}, nil
+	case typ == "bool":
+		return abiType{kind: kindBool}, nil
+	case typ == "string":
+		return abiType{kind: kindString}, nil
+	case typ == "bytes":
+		return abiType{kind: kindBytes}, nil
+	case typ == "tuple":
+		t := abiType{kind: kindTuple}
+		for _, c := range arg.Components {
+			component, err := parseType(c)
+			if err != nil {
+				return abiType{}, err
+			}
+			t.components = append(t.components, component)
+			t.names = append(t.names, c.Name)
+		}
+		return t, nil
+	case strings.HasPrefix(typ, "uint"):
+		size, err := intSize(typ[4:])
+		return abiType{kind: kindUint, size: size}, err
+	case strings.HasPrefix(typ, "int"):
+		size, err := intSize(typ[3:])
+		return abiType{kind: kindInt, size: size}, err
+	case strings.HasPrefix(typ, "bytes"):
+		size, err := strconv.Atoi(typ[5:])
+		if err != nil || size < 1 || size > 32 {
+			return abiType{}, fmt.Errorf("invalid size of %s", typ)
+		}
+		return abiType{kind: kindFixedBytes, size: size}, nil
+	}
+
+	return abiType{}, fmt.Errorf("unknown type %s", typ)
+}
+
+//intSize returns bit size of an integer type, it is 256 if not set
+func intSize(str string) (int, error) {
+	if str == "" {
+		return 256, nil
+	}
+	size, err := strconv.Atoi(str)
+	if err != nil || size < 8 || size > 256 || size%8 != 0 {
+		return 0, fmt.Errorf("invalid integer size %s", str)
+	}
+	return size, nil
+}
+
+//canonical returns type in the form that is used in signatures
+func (t abiType) canonical() string {
+	switch t.kind {
+	case kindUint:
+		return "uint" + strconv.Itoa(t.size)
+	case kindInt:
+		return "int" + strconv.Itoa(t.size)
+	case kindAddress:
+		return "address"
+	case kindBool:
+		return "bool"
+	case kindFixedBytes:
+		return "bytes" + strconv.Itoa(t.size)
+	case kindBytes:
+		return "bytes"
+	case kindString:
+		return "string"
+	case kindSlice:
+		return t.elem.canonical() + "[]"
+	case kindArray:
+		return t.elem.canonical() + "[" + strconv.Itoa(t.length) + "]"
+	}
+
+	components := make([]string, len(t.components))
+	for i, c := range t.components {
+		components[i] = c.canonical()
+	}
+	return "(" + strings.Join(components, ",") + ")"
+}
+
+//dynamic checks whether value of type is encoded out of place with an offset
+func (t abiType) dynamic() bool {
+	switch t.kind {
+	case kindBytes, kindString, kindSlice:
+		return true
+	case kindArray:
+		return t.elem.dynamic()
+	case kindTuple:
+		for _, c := range t.components {
+			if c.dynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//headSize returns number of bytes that type takes in head of its enclosing tuple
+func (t abiType) headSize() int {
+	if t.dynamic() {
+		return 32
+	}
+	switch t.kind {
+	case kindArray:
+		return t.length * t.elem.headSize()
+	case kindTuple:
+		size := 0
+		for _, c := range t.components {
+			size += c.headSize()
+		}
+		return size
+	}
+	return 32
+}
@@ -0,0 +1,82 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const erc20 = `[
+	{"type": "function", "name": "transfer", "inputs": [
+		{"name": "to", "type": "address"},
+		{"name": "value", "type": "uint256"}
+	]},
+	{"type": "event", "name": "Transfer", "inputs": [
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "to", "type": "address", "indexed": true},
+		{"name": "value", "type": "uint256"}
+	]},
+	{"type": "constructor", "inputs": [{"name": "supply", "type": "uint256"}]}
+]`
+
+func TestParse(t *testing.T) {
+	a, err := Parse([]byte(erc20))
+	require.NoError(t, err)
+
+	require.Len(t, a.Functions, 1)
+	transfer, ok := a.Functions["A9059CBB"]
+	require.True(t, ok)
+	require.Equal(t, "transfer(address,uint256)", transfer.Signature)
+
+	require.Len(t, a.Events, 1)
+	event, ok := a.Events["DDF252AD1BE2C89B69C2B068FC378DAA952BA7F163C4A11628F55A4DF523B3EF"]
+	require.True(t, ok)
+	require.Equal(t, "Transfer(address,address,uint256)", event.Signature)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse([]byte(`{"type": "function"}`))
+	require.Error(t, err)
+
+	_, err = Parse([]byte(`[{"type": "function", "inputs": []}]`))
+	require.Error(t, err)
+
+	_, err = Parse([]byte(`[{"type": "function", "name": "f", "inputs": [{"name": "a", "type": "uint7"}]}]`))
+	require.Error(t, err)
+}
+
+func TestSignatureOf(t *testing.T) {
+	tests := []struct {
+		input     Argument
+		canonical string
+		err       bool
+	}{
+		{Argument{Type: "uint"}, "uint256", false},
+		{Argument{Type: "int8"}, "int8", false},
+		{Argument{Type: "bytes32[]"}, "bytes32[]", false},
+		{Argument{Type: "string[2][]"}, "string[2][]", false},
+		{Argument{Type: "tuple[]", Components: []Argument{{Type: "address"}, {Type: "bytes"}}}, "(address,bytes)[]", false},
+		{Argument{Type: "uint257"}, "", true},
+		{Argument{Type: "bytes33"}, "", true},
+		{Argument{Type: "uint256[0]"}, "", true},
+		{Argument{Type: "fixed"}, "", true},
+	}
+
+	for _, test := range tests {
+		signature, err := signatureOf("f", []Argument{test.input})
+		if test.err {
+			require.Error(t, err, test.input.Type)
+			continue
+		}
+		require.NoError(t, err, test.input.Type)
+		require.Equal(t, "f("+test.canonical+")", signature)
+	}
+}
+
+func TestIsAddress(t *testing.T) {
+	require.True(t, IsAddress("0x5B38DA6A701C568545DCFCB03FCB875F56BEDDC4"))
+	require.True(t, IsAddress("5b38da6a701c568545dcfcb03fcb875f56beddc4"))
+	require.False(t, IsAddress("5B38DA6A701C568545DCFCB03FCB875F56BEDD"))
+	require.False(t, IsAddress("../../etc/passwd"))
+	require.False(t, IsAddress(""))
+}
@@ -0,0 +1,295 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+//DecodedArg is a decoded argument, integers are decimal strings, addresses and bytes are
+//upper case hex, arrays are lists of values and tuples are lists of decoded args
+type DecodedArg struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+//DecodedCall is a decoded call of a contract function
+type DecodedCall struct {
+	Function  string
+	Signature string
+	Selector  string
+	Args      []DecodedArg
+}
+
+//DecodedEvent is a decoded log of a contract event. Indexed args of dynamic types
+//are only saved as hash in topics, so their value is the hash
+type DecodedEvent struct {
+	Event     string
+	Signature string
+	Args      []DecodedArg
+}
+
+//DecodeCall decodes hex input data of a call by function that its selector points to
+func (a *ABI) DecodeCall(data string) (*DecodedCall, error) {
+	input, err := decodeHex(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(input) < 4 {
+		return nil, fmt.Errorf("call data is shorter than a selector")
+	}
+
+	selector := strings.ToUpper(hex.EncodeToString(input[:4]))
+	method, ok := a.Functions[selector]
+	if !ok {
+		return nil, fmt.Errorf("unknown function selector %s", selector)
+	}
+
+	args, err := decodeArgs(method.Inputs, input[4:])
+	if err != nil {
+		return nil, fmt.Errorf("error on decoding args of %s: %s", method.Signature, err.Error())
+	}
+
+	return &DecodedCall{Function: method.Name, Signature: method.Signature, Selector: selector, Args: args}, nil
+}
+
+//DecodeLog decodes topics and hex data of a log by event that its first topic points to
+func (a *ABI) DecodeLog(topics []string, data string) (*DecodedEvent, error) {
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("log has no topics")
+	}
+
+	event, ok := a.Events[normalizeHex(topics[0])]
+	if !ok {
+		return nil, fmt.Errorf("unknown event topic %s", topics[0])
+	}
+
+	indexed := make([]Argument, 0)
+	nonIndexed := make([]Argument, 0)
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		} else {
+			nonIndexed = append(nonIndexed, input)
+		}
+	}
+	if len(indexed) != len(topics)-1 {
+		return nil, fmt.Errorf("%s has %d indexed args but log has %d topics", event.Signature, len(indexed), len(topics))
+	}
+
+	input, err := decodeHex(data)
+	if err != nil {
+		return nil, err
+	}
+	values, err := decodeArgs(nonIndexed, input)
+	if err != nil {
+		return nil, fmt.Errorf("error on decoding data of %s: %s", event.Signature, err.Error())
+	}
+
+	args := make([]DecodedArg, 0, len(event.Inputs))
+	nextTopic, nextValue := 1, 0
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			args = append(args, values[nextValue])
+			nextValue++
+			continue
+		}
+
+		arg, err := decodeTopic(input, topics[nextTopic])
+		if err != nil {
+			return nil, fmt.Errorf("error on decoding topic of %s: %s", event.Signature, err.Error())
+		}
+		args = append(args, arg)
+		nextTopic++
+	}
+
+	return &DecodedEvent{Event: event.Name, Signature: event.Signature, Args: args}, nil
+}
+
+//decodeTopic decodes an indexed arg, values of dynamic types are replaced by their hash
+func decodeTopic(input Argument, topic string) (DecodedArg, error) {
+	t, err := parseType(input)
+	if err != nil {
+		return DecodedArg{}, err
+	}
+
+	word, err := decodeHex(topic)
+	if err != nil {
+		return DecodedArg{}, err
+	}
+	if len(word) != 32 {
+		return DecodedArg{}, fmt.Errorf("topic is not 32 bytes")
+	}
+
+	arg := DecodedArg{Name: input.Name, Type: t.canonical()}
+	if t.dynamic() || t.kind == kindArray || t.kind == kindTuple {
+		arg.Value = strings.ToUpper(hex.EncodeToString(word))
+		return arg, nil
+	}
+
+	arg.Value, err = decodeValue(t, word, 0)
+	return arg, err
+}
+
+//decodeArgs decodes args that are encoded together as a tuple
+func decodeArgs(inputs []Argument, data []byte) ([]DecodedArg, error) {
+	t := abiType{kind: kindTuple}
+	for _, input := range inputs {
+		component, err := parseType(input)
+		if err != nil {
+			return nil, err
+		}
+		t.components = append(t.components, component)
+		t.names = append(t.names, input.Name)
+	}
+
+	return decodeTuple(t, data, 0)
+}
+
+//decodeTuple decodes components of a tuple whose head starts at offset of data
+func decodeTuple(t abiType, data []byte, offset int) ([]DecodedArg, error) {
+	args := make([]DecodedArg, len(t.components))
+	head := offset
+	for i, c := range t.components {
+		value, err := decodeHead(c, data, offset, head)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = DecodedArg{Name: t.names[i], Type: c.canonical(), Value: value}
+		head += c.headSize()
+	}
+	return args, nil
+}
+
+//decodeHead decodes a value whose head is at head of data. Offsets of dynamic values
+//are relative to start of their enclosing tuple or array that is at base
+func decodeHead(t abiType, data []byte, base int, head int) (interface{}, error) {
+	if !t.dynamic() {
+		return decodeValue(t, data, head)
+	}
+
+	word, err := readWord(data, head)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := toInt(word)
+	if err != nil {
+		return nil, err
+	}
+	return decodeValue(t, data, base+offset)
+}
+
+//decodeValue decodes a value that starts at offset of data
+func decodeValue(t abiType, data []byte, offset int) (interface{}, error) {
+	switch t.kind {
+	case kindTuple:
+		return decodeTuple(t, data, offset)
+	case kindArray:
+		return decodeList(*t.elem, t.length, data, offset)
+	case kindSlice:
+		word, err := readWord(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		length, err := toInt(word)
+		if err != nil {
+			return nil, err
+		}
+		return decodeList(*t.elem, length, data, offset+32)
+	case kindBytes, kindString:
+		word, err := readWord(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		length, err := toInt(word)
+		if err != nil {
+			return nil, err
+		}
+		if offset+32+length > len(data) {
+			return nil, fmt.Errorf("value is out of data")
+		}
+		value := data[offset+32 : offset+32+length]
+		if t.kind == kindString {
+			return string(value), nil
+		}
+		return strings.ToUpper(hex.EncodeToString(value)), nil
+	}
+
+	word, err := readWord(data, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.kind {
+	case kindUint:
+		return new(big.Int).SetBytes(word).String(), nil
+	case kindInt:
+		value := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return value.String(), nil
+	case kindAddress:
+		return strings.ToUpper(hex.EncodeToString(word[12:])), nil
+	case kindBool:
+		return word[31] == 1, nil
+	case kindFixedBytes:
+		return strings.ToUpper(hex.EncodeToString(word[:t.size])), nil
+	}
+
+	return nil, fmt.Errorf("unknown type %s", t.canonical())
+}
+
+//decodeList decodes length items of an array or a slice that starts at offset of data
+func decodeList(elem abiType, length int, data []byte, offset int) ([]interface{}, error) {
+	if length > len(data)/32+1 {
+		return nil, fmt.Errorf("array length %d is out of data", length)
+	}
+
+	values := make([]interface{}, length)
+	head := offset
+	for i := range values {
+		value, err := decodeHead(elem, data, offset, head)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+		head += elem.headSize()
+	}
+	return values, nil
+}
+
+func readWord(data []byte, offset int) ([]byte, error) {
+	if offset < 0 || offset+32 > len(data) {
+		return nil, fmt.Errorf("value is out of data")
+	}
+	return data[offset : offset+32], nil
+}
+
+//toInt reads an offset or a length, they can not be larger than data
+func toInt(word []byte) (int, error) {
+	value := new(big.Int).SetBytes(word)
+	if !value.IsInt64() || value.Int64() > 1<<32 {
+		return 0, fmt.Errorf("offset or length is too large")
+	}
+	return int(value.Int64()), nil
+}
+
+//normalizeHex removes 0x prefix of hex and converts it to upper case
+func normalizeHex(str string) string {
+	str = strings.TrimSpace(str)
+	if strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X") {
+		str = str[2:]
+	}
+	return strings.ToUpper(str)
+}
+
+func decodeHex(str string) ([]byte, error) {
+	data, err := hex.DecodeString(normalizeHex(str))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex data: %s", err.Error())
+	}
+	return data, nil
+}
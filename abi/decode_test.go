@@ -0,0 +1,238 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+//num encodes n as a 32 bytes word
+func num(n uint64) string {
+	return fmt.Sprintf("%064x", n)
+}
+
+//text encodes s as right padded words
+func text(s string) string {
+	str := hex.EncodeToString([]byte(s))
+	if pad := len(str) % 64; pad != 0 {
+		str += strings.Repeat("0", 64-pad)
+	}
+	return str
+}
+
+const (
+	testAddress = "5B38DA6A701C568545DCFCB03FCB875F56BEDDC4"
+	maxWord     = "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+)
+
+func TestDecodeArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []Argument
+		data   string
+		values []interface{}
+	}{
+		{
+			name: "static",
+			inputs: []Argument{
+				{Name: "a", Type: "uint256"},
+				{Name: "b", Type: "int64"},
+				{Name: "c", Type: "address"},
+				{Name: "d", Type: "bool"},
+				{Name: "e", Type: "bytes4"},
+			},
+			data:   num(42) + maxWord + num(0)[:24] + strings.ToLower(testAddress) + num(1) + text("\xde\xad\xbe\xef"),
+			values: []interface{}{"42", "-1", testAddress, true, "DEADBEEF"},
+		},
+		{
+			name: "dynamic",
+			inputs: []Argument{
+				{Name: "s", Type: "string"},
+				{Name: "b", Type: "bytes"},
+				{Name: "n", Type: "uint256"},
+			},
+			data:   num(0x60) + num(0xa0) + num(7) + num(5) + text("hello") + num(2) + text("\xab\xcd"),
+			values: []interface{}{"hello", "ABCD", "7"},
+		},
+		{
+			name: "empty dynamic",
+			inputs: []Argument{
+				{Name: "s", Type: "string"},
+				{Name: "l", Type: "uint256[]"},
+			},
+			data:   num(0x40) + num(0x60) + num(0) + num(0),
+			values: []interface{}{"", []interface{}{}},
+		},
+		{
+			name: "static tuple",
+			inputs: []Argument{
+				{Name: "p", Type: "tuple", Components: []Argument{{Name: "x", Type: "uint256"}, {Name: "y", Type: "bool"}}},
+				{Name: "n", Type: "uint8"},
+			},
+			data: num(1) + num(1) + num(9),
+			values: []interface{}{
+				[]DecodedArg{{Name: "x", Type: "uint256", Value: "1"}, {Name: "y", Type: "bool", Value: true}},
+				"9",
+			},
+		},
+		{
+			name: "dynamic tuple",
+			inputs: []Argument{
+				{Name: "p", Type: "tuple", Components: []Argument{{Name: "s", Type: "string"}, {Name: "n", Type: "uint256"}}},
+			},
+			//offset of string is relative to start of tuple
+			data: num(0x20) + num(0x40) + num(3) + num(2) + text("hi"),
+			values: []interface{}{
+				[]DecodedArg{{Name: "s", Type: "string", Value: "hi"}, {Name: "n", Type: "uint256", Value: "3"}},
+			},
+		},
+		{
+			name: "fixed array",
+			inputs: []Argument{
+				{Name: "a", Type: "uint256[2]"},
+				{Name: "n", Type: "uint256"},
+			},
+			data:   num(1) + num(2) + num(3),
+			values: []interface{}{[]interface{}{"1", "2"}, "3"},
+		},
+		{
+			name: "slice",
+			inputs: []Argument{
+				{Name: "l", Type: "uint256[]"},
+			},
+			data:   num(0x20) + num(2) + num(5) + num(6),
+			values: []interface{}{[]interface{}{"5", "6"}},
+		},
+		{
+			name: "slice of strings",
+			inputs: []Argument{
+				{Name: "l", Type: "string[]"},
+			},
+			//offsets of items are relative to first item head
+			data:   num(0x20) + num(2) + num(0x40) + num(0x80) + num(1) + text("a") + num(1) + text("b"),
+			values: []interface{}{[]interface{}{"a", "b"}},
+		},
+		{
+			name: "slice of tuples",
+			inputs: []Argument{
+				{Name: "l", Type: "tuple[]", Components: []Argument{{Name: "a", Type: "address"}, {Name: "v", Type: "uint256"}}},
+			},
+			data: num(0x20) + num(1) + num(0)[:24] + strings.ToLower(testAddress) + num(8),
+			values: []interface{}{[]interface{}{
+				[]DecodedArg{{Name: "a", Type: "address", Value: testAddress}, {Name: "v", Type: "uint256", Value: "8"}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		data, err := hex.DecodeString(test.data)
+		require.NoError(t, err, test.name)
+
+		args, err := decodeArgs(test.inputs, data)
+		require.NoError(t, err, test.name)
+		require.Len(t, args, len(test.values), test.name)
+		for i, arg := range args {
+			require.Equal(t, test.inputs[i].Name, arg.Name, test.name)
+			require.Equal(t, test.values[i], arg.Value, test.name)
+		}
+	}
+}
+
+func TestDecodeArgsTruncated(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []Argument
+		data   string
+	}{
+		{"short word", []Argument{{Type: "uint256"}}, num(1)[:62]},
+		{"missing arg", []Argument{{Type: "uint256"}, {Type: "bool"}}, num(1)},
+		{"offset out of data", []Argument{{Type: "string"}}, num(0x40) + num(1)},
+		{"offset too large", []Argument{{Type: "bytes"}}, maxWord},
+		{"length out of data", []Argument{{Type: "string"}}, num(0x20) + num(33) + text("a")},
+		{"slice length out of data", []Argument{{Type: "uint256[]"}}, num(0x20) + num(1000) + num(1)},
+		{"slice item missing", []Argument{{Type: "uint256[]"}}, num(0x20) + num(2) + num(1)},
+		{"fixed array item missing", []Argument{{Type: "uint256[3]"}}, num(1) + num(2)},
+		{"tuple component missing", []Argument{{Type: "tuple", Components: []Argument{{Type: "uint256"}, {Type: "uint256"}}}}, num(1)},
+		{"item offset out of data", []Argument{{Type: "string[]"}}, num(0x20) + num(1) + num(0x1000)},
+	}
+
+	for _, test := range tests {
+		data, err := hex.DecodeString(test.data)
+		require.NoError(t, err, test.name)
+
+		_, err = decodeArgs(test.inputs, data)
+		require.Error(t, err, test.name)
+	}
+}
+
+func TestDecodeCall(t *testing.T) {
+	a, err := Parse([]byte(erc20))
+	require.NoError(t, err)
+
+	call, err := a.DecodeCall("0xa9059cbb" + num(0)[:24] + testAddress + num(1000))
+	require.NoError(t, err)
+	require.Equal(t, "transfer", call.Function)
+	require.Equal(t, "A9059CBB", call.Selector)
+	require.Equal(t, []DecodedArg{
+		{Name: "to", Type: "address", Value: testAddress},
+		{Name: "value", Type: "uint256", Value: "1000"},
+	}, call.Args)
+
+	_, err = a.DecodeCall("a905")
+	require.Error(t, err)
+	_, err = a.DecodeCall("12345678" + num(1))
+	require.Error(t, err)
+	_, err = a.DecodeCall("a9059cbb" + num(1))
+	require.Error(t, err)
+	_, err = a.DecodeCall("zz")
+	require.Error(t, err)
+}
+
+func TestDecodeLog(t *testing.T) {
+	a, err := Parse([]byte(erc20))
+	require.NoError(t, err)
+
+	topic := "DDF252AD1BE2C89B69C2B068FC378DAA952BA7F163C4A11628F55A4DF523B3EF"
+	from := num(0)[:24] + testAddress
+	to := num(0)[:24] + strings.Repeat("11", 20)
+
+	event, err := a.DecodeLog([]string{"0x" + strings.ToLower(topic), from, to}, num(5))
+	require.NoError(t, err)
+	require.Equal(t, "Transfer", event.Event)
+	require.Equal(t, []DecodedArg{
+		{Name: "from", Type: "address", Value: testAddress},
+		{Name: "to", Type: "address", Value: strings.Repeat("11", 20)},
+		{Name: "value", Type: "uint256", Value: "5"},
+	}, event.Args)
+
+	_, err = a.DecodeLog(nil, "")
+	require.Error(t, err)
+	_, err = a.DecodeLog([]string{num(1)}, "")
+	require.Error(t, err)
+	_, err = a.DecodeLog([]string{topic, from}, num(5))
+	require.Error(t, err)
+	_, err = a.DecodeLog([]string{topic, from[2:], to}, num(5))
+	require.Error(t, err)
+	_, err = a.DecodeLog([]string{topic, from, to}, "")
+	require.Error(t, err)
+}
+
+func TestDecodeIndexedDynamic(t *testing.T) {
+	a, err := Parse([]byte(`[{"type": "event", "name": "Named", "inputs": [
+		{"name": "name", "type": "string", "indexed": true}
+	]}]`))
+	require.NoError(t, err)
+
+	var topic string
+	for key := range a.Events {
+		topic = key
+	}
+	hash := strings.Repeat("AB", 32)
+
+	event, err := a.DecodeLog([]string{topic, hash}, "")
+	require.NoError(t, err)
+	require.Equal(t, []DecodedArg{{Name: "name", Type: "string", Value: hash}}, event.Args)
+}
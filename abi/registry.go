@@ -0,0 +1,69 @@
+package abi
+
+import (
+	"sync"
+)
+
+//Loader returns saved ABI json of a contract or empty string if it has no ABI
+type Loader func(address string) (string, error)
+
+//Registry keeps parsed ABIs of contracts that are loaded by loader.
+//Contracts without ABI are cached too, so they are not loaded again until they are reset
+type Registry struct {
+	load Loader
+	mtx  sync.RWMutex
+	abis map[string]*ABI
+}
+
+//NewRegistry creates a registry that loads ABIs by loader
+func NewRegistry(load Loader) *Registry {
+	return &Registry{
+		load: load,
+		abis: make(map[string]*ABI),
+	}
+}
+
+//Get returns ABI of contract address or nil if contract has no ABI
+func (r *Registry) Get(address string) (*ABI, error) {
+	address = normalizeHex(address)
+
+	r.mtx.RLock()
+	a, ok := r.abis[address]
+	r.mtx.RUnlock()
+	if ok {
+		return a, nil
+	}
+
+	data, err := r.load(address)
+	if err != nil {
+		return nil, err
+	}
+	if data != "" {
+		if a, err = Parse([]byte(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mtx.Lock()
+	r.abis[address] = a
+	r.mtx.Unlock()
+	return a, nil
+}
+
+//Reset removes cached ABI of contract address, it is loaded again next time
+func (r *Registry) Reset(address string) {
+	r.mtx.Lock()
+	delete(r.abis, normalizeHex(address))
+	r.mtx.Unlock()
+}
+
+//NormalizeAddress returns address in the form that ABIs are saved with
+func NormalizeAddress(address string) string {
+	return normalizeHex(address)
+}
+
+//IsAddress checks whether address is 20 bytes of hex, with or without 0x prefix
+func IsAddress(address string) bool {
+	data, err := decodeHex(address)
+	return err == nil && len(data) == 20
+}
@@ -27,7 +27,7 @@ type BlockInfo struct {
 	NumTxs   int64
 	TotalTxs int64
 	// prev block info
-	//LastBlockHash string
+	LastBlockHash string
 	// hashes of block data
 	LastCommitHash string
 	DataHash       string
@@ -40,21 +40,38 @@ type BlockInfo struct {
 	// consensus info
 	EvidenceHash    string
 	ProposerAddress string
+	// validators that signed commit of previous block and number of validators in its set,
+	// they are only read by GetBlockInfo
+	LastCommitSigners []string
+	LastCommitSize    int
 }
 
 //Block struct
 type Block struct {
-	Height  int64
-	Hash    string
-	ChainID string
-	Time    time.Time
-	//LastBlockHash string
+	Height   int64
+	Hash     string
+	ChainID  string
+	Time     time.Time
 	TxCounts int64
 	Duration uint64
+	// header info
+	TotalTxs           int64
+	LastBlockHash      string
+	LastCommitHash     string
+	DataHash           string
+	ValidatorsHash     string
+	NextValidatorsHash string
+	ConsensusHash      string
+	AppHash            string
+	LastResultsHash    string
+	EvidenceHash       string
+	ProposerAddress    string
 }
 
 //Transaction struct
 type Transaction struct {
+	//ID is only used for cursors of pages, it is not part of v1 responses
+	ID       uint64 `json:"-"`
 	Type     string
 	BlockID  int64
 	Hash     string
@@ -65,6 +82,121 @@ type Transaction struct {
 	Fee      uint64
 	Data     string
 	Sequence uint64
+	Inputs   []TxIO
+	Outputs  []TxIO
+	//Name is only set for NameTxs, data of a NameTx is data of its name
+	Name string
+}
+
+//Addresses returns distinct addresses that take part in tx as its sender, receiver,
+//inputs or outputs
+func (tx *Transaction) Addresses() []string {
+	addresses := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(address string) {
+		if address != "" && !seen[address] {
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+
+	add(tx.From)
+	add(tx.To)
+	for _, input := range tx.Inputs {
+		add(input.Address)
+	}
+	for _, output := range tx.Outputs {
+		add(output.Address)
+	}
+	return addresses
+}
+
+//TxExecution is result of executing a transaction
+type TxExecution struct {
+	TxHash          string
+	Height          int64
+	Index           int64
+	Succeeded       bool
+	GasUsed         uint64
+	Return          string
+	ExceptionCode   int64
+	Exception       string
+	CreatesContract bool
+	ContractAddress string
+	Events          []TxEvent
+}
+
+//TxEvent is an event that is emitted while executing a transaction, log fields are only set for log events
+type TxEvent struct {
+	Index     int64
+	EventType string
+	EventID   string
+	Data      string
+	Address   string
+	Topics    []string
+	LogData   string
+}
+
+//Contract is a contract that is deployed by a CallTx without address,
+//code hash is keccak256 of its deployed code
+type Contract struct {
+	Address  string
+	Creator  string
+	TxHash   string
+	Height   int64
+	CodeHash string
+}
+
+//NameEntry is an entry of name registry after a NameTx, entry is expired after expires height
+type NameEntry struct {
+	Name    string
+	Data    string
+	Owner   string
+	Expires int64
+	Height  int64
+	TxHash  string
+	Deleted bool
+}
+
+//ValidatorPowerChange is a change of voting power of a validator that is caused by a BondTx or an UnbondTx
+type ValidatorPowerChange struct {
+	Height  int64
+	TxHash  string
+	Address string
+	Delta   int64
+	Power   uint64
+}
+
+//PermissionChange is a change of permissions or roles of an account that is caused by a
+//PermsTx or a GovTx, permissions are names of flags that the action is done on
+type PermissionChange struct {
+	Height      int64
+	TxHash      string
+	Index       int64
+	Address     string
+	Sender      string
+	Action      string
+	Permissions []string
+	Value       *bool
+	Roles       []string
+}
+
+//BalanceChange is a change of balance of an address that is caused by a transaction,
+//index is position of change in its block
+type BalanceChange struct {
+	Height  int64
+	TxHash  string
+	Index   int64
+	Address string
+	Delta   int64
+	Reason  string
+}
+
+//TxIO is an input or an output of a transaction, sequence is only set for inputs
+type TxIO struct {
+	Address  string
+	Amount   uint64
+	Sequence uint64
 }
 
 //NodeConnectionStatus for get status of connection
@@ -94,6 +226,27 @@ type StatusSyncInfo struct {
 	LatestBlockDuration uint64 `json:"LatestBlockDuration"`
 }
 
+//Validator is a bonded validator of network
+type Validator struct {
+	Address   string
+	PublicKey string
+	Power     uint64
+}
+
+//ValidatorSet is the set of bonded validators at a height
+type ValidatorSet struct {
+	Height     int64
+	Validators []Validator
+}
+
+//Genesis is the genesis document of chain, its validators are in set from first block
+//and its accounts hold opening balances before first block
+type Genesis struct {
+	ChainName  string
+	Validators []Validator
+	Accounts   []Account
+}
+
 //Adapter for data base
 type Adapter interface {
 	CreateClient() error
@@ -101,7 +254,7 @@ type Adapter interface {
 	Update() error
 
 	GetAccountsCount() int
-	GetAccount(id int) (*Account, error)
+	GetAccount(address string) (*Account, error)
 	GetAccounts() ([]*Account, error)
 
 	GetBlocksLastHeight() (uint64, error)
@@ -112,9 +265,17 @@ type Adapter interface {
 
 	GetTXsCount(height uint64) int
 	GetTx(height uint64, hash []byte) (*Transaction, error)
+	GetTxByHash(hash []byte) (*Transaction, error)
+	//SupportsTxLookup checks whether node serves lookup of txs by hash that GetTxByHash needs
+	SupportsTxLookup() (bool, error)
 	GetTXs(height uint64) ([]Transaction, error)
+	GetTxExecutions(height uint64) ([]TxExecution, error)
+	SupportsTxExecutions() (bool, error)
 
 	GetNodes() ([]Peer, error)
 
 	GetSyncInfo() (*StatusSyncInfo, error) 
+
+	GetValidators() (*ValidatorSet, error)
+	GetGenesis() (*Genesis, error)
 }
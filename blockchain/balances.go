@@ -0,0 +1,164 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+//Reasons of balance changes
+const (
+	ReasonInput     = "input"
+	ReasonOutput    = "output"
+	ReasonFee       = "fee"
+	ReasonCall      = "call"
+	ReasonReconcile = "reconcile"
+	ReasonGenesis   = "genesis"
+)
+
+//BalanceChanges returns balance changes that are caused by txs of a block. Inputs pay the fee
+//and the value, outputs and called or created contracts receive the value. Txs whose execution
+//failed only pay the fee and value transfers between contracts are read from call events.
+//Outcome of a tx without execution is unknown, so it is skipped and its changes are left to reconcile
+func BalanceChanges(txs []Transaction, execs []TxExecution, contracts []Contract) []BalanceChange {
+	results := make(map[string]*TxExecution)
+	for i := range execs {
+		results[strings.ToUpper(execs[i].TxHash)] = &execs[i]
+	}
+	created := make(map[string]string)
+	for _, c := range contracts {
+		created[strings.ToUpper(c.TxHash)] = c.Address
+	}
+
+	changes := make([]BalanceChange, 0)
+	for i := range txs {
+		tx := &txs[i]
+		exec := results[strings.ToUpper(tx.Hash)]
+		if exec == nil {
+			continue
+		}
+		failed := !exec.Succeeded
+
+		add := func(address string, delta int64, reason string) {
+			if address == "" || delta == 0 {
+				return
+			}
+			changes = append(changes, BalanceChange{
+				Height:  tx.BlockID,
+				TxHash:  tx.Hash,
+				Index:   int64(len(changes)),
+				Address: strings.ToUpper(address),
+				Delta:   delta,
+				Reason:  reason,
+			})
+		}
+
+		fee := txFee(tx)
+		switch tx.Type {
+		case "UnbondTx":
+			//input of unbond is the validator whose power is decreased, only output is paid
+			for _, output := range tx.Outputs {
+				add(output.Address, int64(output.Amount), ReasonOutput)
+			}
+			continue
+		case "BatchTx":
+			//balances of inner txs are fixed by reconciling with node
+			continue
+		}
+
+		for j, input := range tx.Inputs {
+			value := int64(input.Amount)
+			if j == 0 {
+				add(input.Address, -int64(fee), ReasonFee)
+				value -= int64(fee)
+			}
+			if !failed {
+				add(input.Address, -value, ReasonInput)
+			}
+		}
+		if failed {
+			continue
+		}
+
+		if tx.Type == "CallTx" {
+			to := tx.To
+			if to == "" {
+				to = created[strings.ToUpper(tx.Hash)]
+			}
+			add(to, int64(tx.Amount)-int64(fee), ReasonOutput)
+		} else {
+			for _, output := range tx.Outputs {
+				add(output.Address, int64(output.Amount), ReasonOutput)
+			}
+		}
+
+		for _, transfer := range callTransfers(exec.Events) {
+			add(transfer.caller, -transfer.value, ReasonCall)
+			add(transfer.callee, transfer.value, ReasonCall)
+		}
+	}
+	return changes
+}
+
+//txFee returns fee that is paid by first input of tx, fee of send txs is what is not sent to outputs
+func txFee(tx *Transaction) uint64 {
+	if tx.Type != "SendTx" {
+		if len(tx.Inputs) > 0 && tx.Fee > tx.Inputs[0].Amount {
+			return tx.Inputs[0].Amount
+		}
+		return tx.Fee
+	}
+
+	var in, out uint64
+	for _, input := range tx.Inputs {
+		in += input.Amount
+	}
+	for _, output := range tx.Outputs {
+		out += output.Amount
+	}
+	if in <= out || len(tx.Inputs) == 0 || in-out > tx.Inputs[0].Amount {
+		return 0
+	}
+	return in - out
+}
+
+//valueTransfer is a transfer of value from a contract to an address in a nested call
+type valueTransfer struct {
+	caller string
+	callee string
+	value  int64
+}
+
+//callTransfers returns value transfers of nested calls, calls at depth zero are the tx itself
+func callTransfers(events []TxEvent) []valueTransfer {
+	type callData struct {
+		Caller string      `json:"Caller"`
+		Callee string      `json:"Callee"`
+		Value  json.Number `json:"Value"`
+	}
+	type callEvent struct {
+		CallData   callData    `json:"CallData"`
+		StackDepth json.Number `json:"StackDepth"`
+	}
+
+	transfers := make([]valueTransfer, 0)
+	for _, event := range events {
+		if !strings.EqualFold(event.EventType, "Call") || event.Data == "" {
+			continue
+		}
+
+		var call callEvent
+		decoder := json.NewDecoder(strings.NewReader(event.Data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&call); err != nil {
+			continue
+		}
+
+		depth, _ := call.StackDepth.Int64()
+		value, _ := call.CallData.Value.Int64()
+		if depth == 0 || value <= 0 {
+			continue
+		}
+		transfers = append(transfers, valueTransfer{caller: call.CallData.Caller, callee: call.CallData.Callee, value: value})
+	}
+	return transfers
+}
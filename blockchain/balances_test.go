@@ -0,0 +1,193 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// change is a balance change without its tx and position
+type change struct {
+	address string
+	delta   int64
+	reason  string
+}
+
+func toChanges(balanceChanges []BalanceChange) []change {
+	changes := make([]change, len(balanceChanges))
+	for i, c := range balanceChanges {
+		changes[i] = change{c.Address, c.Delta, c.Reason}
+	}
+	return changes
+}
+
+func TestBalanceChanges(t *testing.T) {
+	callEvents := []TxEvent{
+		{EventType: "Call", Data: `{"CallData":{"Caller":"C1","Callee":"D1","Value":"0"},"StackDepth":0}`},
+		{EventType: "Call", Data: `{"CallData":{"Caller":"C1","Callee":"E1","Value":"15"},"StackDepth":1}`},
+		{EventType: "Call", Data: `{"CallData":{"Caller":"E1","Callee":"F1","Value":"0"},"StackDepth":2}`},
+		{EventType: "Log", Data: `{"Value":"99"}`},
+	}
+
+	tests := []struct {
+		name      string
+		tx        Transaction
+		execs     []TxExecution
+		contracts []Contract
+		//unknown leaves tx without execution, other txs succeed if execs is not set
+		unknown bool
+		changes []change
+	}{
+		{
+			name: "send",
+			tx: Transaction{Hash: "T", Type: "SendTx",
+				Inputs:  []TxIO{{Address: "a1", Amount: 105}},
+				Outputs: []TxIO{{Address: "b1", Amount: 60}, {Address: "b2", Amount: 40}}},
+			changes: []change{
+				{"A1", -5, ReasonFee}, {"A1", -100, ReasonInput},
+				{"B1", 60, ReasonOutput}, {"B2", 40, ReasonOutput},
+			},
+		},
+		{
+			name: "send without fee",
+			tx: Transaction{Hash: "T", Type: "SendTx",
+				Inputs:  []TxIO{{Address: "A1", Amount: 50}, {Address: "A2", Amount: 50}},
+				Outputs: []TxIO{{Address: "B1", Amount: 100}}},
+			changes: []change{
+				{"A1", -50, ReasonInput}, {"A2", -50, ReasonInput}, {"B1", 100, ReasonOutput},
+			},
+		},
+		{
+			name: "call",
+			tx: Transaction{Hash: "T", Type: "CallTx", To: "C1", Amount: 100, Fee: 10,
+				Inputs: []TxIO{{Address: "A1", Amount: 100}}},
+			changes: []change{
+				{"A1", -10, ReasonFee}, {"A1", -90, ReasonInput}, {"C1", 90, ReasonOutput},
+			},
+		},
+		{
+			name: "call with nested transfers",
+			tx: Transaction{Hash: "T", Type: "CallTx", To: "C1", Amount: 100, Fee: 10,
+				Inputs: []TxIO{{Address: "A1", Amount: 100}}},
+			execs: []TxExecution{{TxHash: "t", Succeeded: true, Events: callEvents}},
+			changes: []change{
+				{"A1", -10, ReasonFee}, {"A1", -90, ReasonInput}, {"C1", 90, ReasonOutput},
+				{"C1", -15, ReasonCall}, {"E1", 15, ReasonCall},
+			},
+		},
+		{
+			name: "failed call only pays fee",
+			tx: Transaction{Hash: "T", Type: "CallTx", To: "C1", Amount: 100, Fee: 10,
+				Inputs: []TxIO{{Address: "A1", Amount: 100}}},
+			execs: []TxExecution{{TxHash: "T", Succeeded: false, Events: callEvents}},
+			changes: []change{
+				{"A1", -10, ReasonFee},
+			},
+		},
+		{
+			name: "call that creates a contract",
+			tx: Transaction{Hash: "T", Type: "CallTx", Amount: 30, Fee: 10,
+				Inputs: []TxIO{{Address: "A1", Amount: 30}}},
+			contracts: []Contract{{TxHash: "T", Address: "c2"}},
+			changes: []change{
+				{"A1", -10, ReasonFee}, {"A1", -20, ReasonInput}, {"C2", 20, ReasonOutput},
+			},
+		},
+		{
+			name: "call that creates a contract without indexed contracts",
+			tx: Transaction{Hash: "T", Type: "CallTx", Amount: 30, Fee: 10,
+				Inputs: []TxIO{{Address: "A1", Amount: 30}}},
+			changes: []change{
+				{"A1", -10, ReasonFee}, {"A1", -20, ReasonInput},
+			},
+		},
+		{
+			name: "unbond only pays output",
+			tx: Transaction{Hash: "T", Type: "UnbondTx",
+				Inputs:  []TxIO{{Address: "V1", Amount: 70}},
+				Outputs: []TxIO{{Address: "A1", Amount: 70}}},
+			changes: []change{
+				{"A1", 70, ReasonOutput},
+			},
+		},
+		{
+			name: "batch is reconciled",
+			tx: Transaction{Hash: "T", Type: "BatchTx",
+				Inputs: []TxIO{{Address: "A1", Amount: 70}}},
+			changes: []change{},
+		},
+		{
+			name: "unknown outcome is skipped",
+			tx: Transaction{Hash: "T", Type: "SendTx",
+				Inputs:  []TxIO{{Address: "A1", Amount: 50}},
+				Outputs: []TxIO{{Address: "B1", Amount: 50}}},
+			execs:   []TxExecution{{TxHash: "T2", Succeeded: true}},
+			changes: []change{},
+		},
+		{
+			name: "unknown outcome without executions is skipped",
+			tx: Transaction{Hash: "T", Type: "CallTx", To: "C1", Amount: 100, Fee: 10,
+				Inputs: []TxIO{{Address: "A1", Amount: 100}}},
+			unknown: true,
+			changes: []change{},
+		},
+		{
+			name: "name pays fee and amount",
+			tx: Transaction{Hash: "T", Type: "NameTx", Fee: 3,
+				Inputs: []TxIO{{Address: "A1", Amount: 50}}},
+			changes: []change{
+				{"A1", -3, ReasonFee}, {"A1", -47, ReasonInput},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test.tx.BlockID = 7
+		if test.execs == nil && !test.unknown {
+			test.execs = []TxExecution{{TxHash: "T", Succeeded: true}}
+		}
+		changes := BalanceChanges([]Transaction{test.tx}, test.execs, test.contracts)
+		require.Equal(t, test.changes, toChanges(changes), test.name)
+		for i, c := range changes {
+			require.Equal(t, int64(7), c.Height, test.name)
+			require.Equal(t, "T", c.TxHash, test.name)
+			require.Equal(t, int64(i), c.Index, test.name)
+		}
+	}
+}
+
+func TestBalanceChangesIndexAcrossTxs(t *testing.T) {
+	txs := []Transaction{
+		{Hash: "T1", Type: "SendTx", Inputs: []TxIO{{Address: "A1", Amount: 5}}, Outputs: []TxIO{{Address: "B1", Amount: 5}}},
+		{Hash: "T2", Type: "SendTx", Inputs: []TxIO{{Address: "A2", Amount: 5}}, Outputs: []TxIO{{Address: "B2", Amount: 5}}},
+	}
+
+	execs := []TxExecution{{TxHash: "T1", Succeeded: true}, {TxHash: "T2", Succeeded: true}}
+	changes := BalanceChanges(txs, execs, nil)
+	require.Len(t, changes, 4)
+	for i, c := range changes {
+		require.Equal(t, int64(i), c.Index)
+	}
+	require.Equal(t, "T2", changes[3].TxHash)
+}
+
+func TestTxFee(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   Transaction
+		fee  uint64
+	}{
+		{"call", Transaction{Type: "CallTx", Fee: 10, Inputs: []TxIO{{Amount: 100}}}, 10},
+		{"call fee is capped by input", Transaction{Type: "CallTx", Fee: 10, Inputs: []TxIO{{Amount: 4}}}, 4},
+		{"call without inputs", Transaction{Type: "CallTx", Fee: 10}, 10},
+		{"send", Transaction{Type: "SendTx", Inputs: []TxIO{{Amount: 60}, {Amount: 50}}, Outputs: []TxIO{{Amount: 100}}}, 10},
+		{"send without fee", Transaction{Type: "SendTx", Inputs: []TxIO{{Amount: 100}}, Outputs: []TxIO{{Amount: 100}}}, 0},
+		{"send with more outputs", Transaction{Type: "SendTx", Inputs: []TxIO{{Amount: 90}}, Outputs: []TxIO{{Amount: 100}}}, 0},
+		{"send fee is more than first input", Transaction{Type: "SendTx", Inputs: []TxIO{{Amount: 5}, {Amount: 100}}, Outputs: []TxIO{{Amount: 90}}}, 0},
+		{"send without inputs", Transaction{Type: "SendTx", Outputs: []TxIO{{Amount: 90}}}, 0},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.fee, txFee(&test.tx), test.name)
+	}
+}
@@ -0,0 +1,81 @@
+package blockchain
+
+import (
+	"strings"
+)
+
+//PowerDelta returns validator and change of its voting power that a BondTx or an UnbondTx makes.
+//Bonded amount of input is added to power of the input account as validator and unbonded
+//amount is removed from it, it is the paid amount if input has no amount. It returns false for other txs
+func PowerDelta(tx *Transaction) (string, int64, bool) {
+	if tx.From == "" {
+		return "", 0, false
+	}
+
+	switch tx.Type {
+	case "BondTx":
+		if tx.Amount > 0 {
+			return strings.ToUpper(tx.From), int64(tx.Amount), true
+		}
+	case "UnbondTx":
+		amount := tx.Amount
+		if amount == 0 {
+			for _, output := range tx.Outputs {
+				amount += output.Amount
+			}
+		}
+		if amount > 0 {
+			return strings.ToUpper(tx.From), -int64(amount), true
+		}
+	}
+	return "", 0, false
+}
+
+//ApplyPowerDelta returns power of validator after delta, power never goes below zero
+func ApplyPowerDelta(power uint64, delta int64) uint64 {
+	if delta < 0 && uint64(-delta) > power {
+		return 0
+	}
+	return uint64(int64(power) + delta)
+}
+
+//PowerChanges returns changes of voting power that BondTxs and UnbondTxs of txs make, txs are in
+//order of execution. Power of a validator is counted from prior for its first tx, that is its
+//genesis power plus changes saved before txs, and from its previous change after that.
+//Txs whose hash is not in succeeded are skipped, they failed or their outcome is unknown
+func PowerChanges(txs []Transaction, succeeded map[string]bool, prior func(address string, beforeHeight int64) (uint64, error)) ([]ValidatorPowerChange, error) {
+	changes := make([]ValidatorPowerChange, 0)
+	powers := make(map[string]uint64)
+
+	for i := range txs {
+		tx := &txs[i]
+		if !succeeded[strings.ToUpper(tx.Hash)] {
+			continue
+		}
+
+		address, delta, ok := PowerDelta(tx)
+		if !ok {
+			continue
+		}
+
+		power, known := powers[address]
+		if !known {
+			saved, err := prior(address, tx.BlockID)
+			if err != nil {
+				return nil, err
+			}
+			power = saved
+		}
+
+		power = ApplyPowerDelta(power, delta)
+		powers[address] = power
+		changes = append(changes, ValidatorPowerChange{
+			Height:  tx.BlockID,
+			TxHash:  tx.Hash,
+			Address: address,
+			Delta:   delta,
+			Power:   power,
+		})
+	}
+	return changes, nil
+}
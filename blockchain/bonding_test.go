@@ -0,0 +1,93 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowerDelta(t *testing.T) {
+	tests := []struct {
+		name    string
+		tx      Transaction
+		address string
+		delta   int64
+		ok      bool
+	}{
+		{"bond", Transaction{Type: "BondTx", From: "v1", Amount: 50}, "V1", 50, true},
+		{"bond without amount", Transaction{Type: "BondTx", From: "V1"}, "", 0, false},
+		{"bond without input", Transaction{Type: "BondTx", Amount: 50}, "", 0, false},
+		{"unbond", Transaction{Type: "UnbondTx", From: "V1", Amount: 30}, "V1", -30, true},
+		{"unbond paid to outputs", Transaction{Type: "UnbondTx", From: "V1",
+			Outputs: []TxIO{{Address: "A1", Amount: 20}, {Address: "A2", Amount: 5}}}, "V1", -25, true},
+		{"unbond without amount", Transaction{Type: "UnbondTx", From: "V1"}, "", 0, false},
+		{"send", Transaction{Type: "SendTx", From: "V1", Amount: 50}, "", 0, false},
+	}
+
+	for _, test := range tests {
+		address, delta, ok := PowerDelta(&test.tx)
+		require.Equal(t, test.ok, ok, test.name)
+		require.Equal(t, test.address, address, test.name)
+		require.Equal(t, test.delta, delta, test.name)
+	}
+}
+
+func TestApplyPowerDelta(t *testing.T) {
+	require.Equal(t, uint64(150), ApplyPowerDelta(100, 50))
+	require.Equal(t, uint64(70), ApplyPowerDelta(100, -30))
+	require.Equal(t, uint64(0), ApplyPowerDelta(100, -100))
+	require.Equal(t, uint64(0), ApplyPowerDelta(100, -101))
+	require.Equal(t, uint64(10), ApplyPowerDelta(0, 10))
+}
+
+func TestPowerChanges(t *testing.T) {
+	//V1 is in genesis set, V2 is bonded after genesis
+	genesis := map[string]uint64{"V1": 100}
+	calls := make([]string, 0)
+	prior := func(address string, beforeHeight int64) (uint64, error) {
+		calls = append(calls, address)
+		return genesis[address], nil
+	}
+
+	txs := []Transaction{
+		{Hash: "T1", BlockID: 5, Type: "BondTx", From: "V1", Amount: 50},
+		{Hash: "T2", BlockID: 5, Type: "SendTx", From: "V1", Amount: 50},
+		{Hash: "T3", BlockID: 6, Type: "BondTx", From: "V2", Amount: 10},
+		{Hash: "t4", BlockID: 6, Type: "BondTx", From: "V2", Amount: 1000},
+		{Hash: "T5", BlockID: 7, Type: "UnbondTx", From: "V1", Amount: 30},
+		{Hash: "T6", BlockID: 8, Type: "UnbondTx", From: "V2", Amount: 40},
+		{Hash: "T7", BlockID: 9, Type: "BondTx", From: "V2", Amount: 5},
+	}
+	//T4 failed and outcome of T8 is unknown
+	txs = append(txs, Transaction{Hash: "T8", BlockID: 9, Type: "BondTx", From: "V1", Amount: 7})
+	succeeded := map[string]bool{"T1": true, "T2": true, "T3": true, "T5": true, "T6": true, "T7": true}
+
+	changes, err := PowerChanges(txs, succeeded, prior)
+	require.NoError(t, err)
+	require.Equal(t, []ValidatorPowerChange{
+		{Height: 5, TxHash: "T1", Address: "V1", Delta: 50, Power: 150},
+		{Height: 6, TxHash: "T3", Address: "V2", Delta: 10, Power: 10},
+		{Height: 7, TxHash: "T5", Address: "V1", Delta: -30, Power: 120},
+		{Height: 8, TxHash: "T6", Address: "V2", Delta: -40, Power: 0},
+		{Height: 9, TxHash: "T7", Address: "V2", Delta: 5, Power: 5},
+	}, changes)
+
+	//prior power is only read once for each validator
+	require.Equal(t, []string{"V1", "V2"}, calls)
+}
+
+func TestPowerChangesPriorError(t *testing.T) {
+	priorErr := errors.New("database is down")
+	prior := func(address string, beforeHeight int64) (uint64, error) {
+		return 0, priorErr
+	}
+
+	txs := []Transaction{{Hash: "T1", BlockID: 5, Type: "BondTx", From: "V1", Amount: 50}}
+	_, err := PowerChanges(txs, map[string]bool{"T1": true}, prior)
+	require.Equal(t, priorErr, err)
+
+	changes, err := PowerChanges([]Transaction{{Hash: "T1", Type: "SendTx"}}, map[string]bool{"T1": true}, prior)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
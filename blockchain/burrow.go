@@ -1,16 +1,18 @@
 package blockchain
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
-	//"strings"
-
 	config "github.com/BurrowBlocks/config"
 )
 
@@ -65,17 +67,37 @@ func (g *Burrow) Update() error {
 	return nil
 }
 
-//GetReply for issuing get to remote url
+//GetReply returns body of reply to a GET request to strURL, it is empty when request fails or
+//node does not reply with 200
 func (g *Burrow) GetReply(strURL string) string {
 
+	body, status, err := g.getResponse(strURL)
+	if err != nil {
+		fmt.Println("\nError reading response body: " + err.Error())
+		return ""
+	}
+
+	// What was the response status from the server?
+	if status != http.StatusOK {
+		fmt.Println("\nError reading response body, status code: " + strconv.Itoa(status))
+		return ""
+	}
+
+	return body
+
+}
+
+//getResponse returns body and status code of reply to a GET request to strURL. It only returns an error
+//when node can not be reached or reply can not be read
+func (g *Burrow) getResponse(strURL string) (string, int, error) {
+
 	defer g.tr.CloseIdleConnections()
 	defer g.client.CloseIdleConnections()
 
 	// Turn it into a request
 	req, err := http.NewRequest("GET", strURL, nil)
 	if err != nil {
-		fmt.Println("\nError forming request: " + err.Error())
-		return ""
+		return "", 0, fmt.Errorf("error forming request: %w", err)
 	}
 
 	req.Header.Set("Connection", "close")
@@ -87,41 +109,23 @@ func (g *Burrow) GetReply(strURL string) string {
 	// Get the URL
 	res, err := g.client.Do(req)
 	if err != nil {
-		fmt.Println("\nError reading response body: " + err.Error())
 		if res != nil {
 			res.Body.Close()
 		}
-		return ""
+		return "", 0, err
 	}
 
 	res.Close = true
-
-	// What was the response status from the server?
-	var strResult string
-	if res.StatusCode != 200 {
-		fmt.Println("\nError reading response body, status code: " + res.Status)
-		if res != nil {
-			res.Body.Close()
-		}
-		return ""
-	}
+	defer res.Body.Close()
 
 	// Read the reply
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		fmt.Println("\nError reading response body: " + err.Error())
-		if res != nil {
-			res.Body.Close()
-		}
-		return ""
+		return "", res.StatusCode, err
 	}
-	res.Body.Close()
 
 	// Cut down on calls to convert this
-	strResult = string(body)
-
-	// Done
-	return strResult
+	return string(body), res.StatusCode, nil
 
 }
 
@@ -223,32 +227,8 @@ func (g *Burrow) GetBlockInfo(height uint64) (*BlockInfo, error) {
 		return nil, err
 	}
 
-	heightStr := fmt.Sprintf("%v", env.Result.Block.Header["height"])
-	blockHeight, _ := strconv.ParseInt(heightStr, 10, 64)
-
-	numTxsStr := fmt.Sprintf("%v", env.Result.Block.Header["num_txs"])
-	numTxs, _ := strconv.ParseInt(numTxsStr, 10, 64)
-
-	totalTxsStr := fmt.Sprintf("%v", env.Result.Block.Header["total_txs"])
-	totalTxs, _ := strconv.ParseInt(totalTxsStr, 10, 64)
-
-	//inf.VersionBlock = 0 //fmt.Sprintf("%v", env.Result.Block.Header["version"])
-	//inf.VersionApp = 0
-	inf.ChainID = fmt.Sprintf("%v", env.Result.Block.Header["chain_id"])
-	inf.Height = blockHeight
-	inf.Time = fmt.Sprintf("%v", env.Result.Block.Header["time"])
-	inf.NumTxs = numTxs
-	inf.TotalTxs = totalTxs
-	inf.BlockHash = fmt.Sprintf("%v", env.Result.BlockMeta.ID.Hash)
-	//inf.LastBlockHash = "" //fmt.Sprintf("%v", env.Result.Block.Header[""])
-	inf.DataHash = fmt.Sprintf("%v", env.Result.Block.Header["data_hash"])
-	inf.ValidatorsHash = fmt.Sprintf("%v", env.Result.Block.Header["validators_hash"])
-	inf.NextValidatorsHash = fmt.Sprintf("%v", env.Result.Block.Header["next_validators_hash"])
-	inf.ConsensusHash = fmt.Sprintf("%v", env.Result.Block.Header["consensus_hash"])
-	inf.AppHash = fmt.Sprintf("%v", env.Result.Block.Header["app_hash"])
-	inf.LastResultsHash = fmt.Sprintf("%v", env.Result.Block.Header["last_results_hash"])
-	inf.EvidenceHash = fmt.Sprintf("%v", env.Result.Block.Header["evidence_hash"])
-	inf.ProposerAddress = fmt.Sprintf("%v", env.Result.Block.Header["proposer_address"])
+	inf = blockInfoFromHeader(env.Result.BlockMeta.ID.Hash, env.Result.Block.Header)
+	inf.LastCommitSigners, inf.LastCommitSize = commitSigners(env.Result.Block.LastCommit)
 
 	return &inf, nil
 }
@@ -256,88 +236,165 @@ func (g *Burrow) GetBlockInfo(height uint64) (*BlockInfo, error) {
 //GetBlock returns specified block
 func (g *Burrow) GetBlock(height uint64) (*Block, error) {
 
-	return nil, nil
+	lastID, lastIDErr := g.GetBlocksLastHeight()
+	if lastIDErr != nil {
+		return nil, lastIDErr
+	}
+	if height > lastID {
+		return nil, fmt.Errorf("block height out of range (max is %d)", lastID)
+	}
 
-	/*
-		lastID, lastIDErr := g.GetBlocksLastHeight()
-		if lastIDErr != nil {
-			return nil, lastIDErr
-		}
-		if height > lastID {
-			return nil, fmt.Errorf("block height out of range (max is " + string(lastID) + ")")
-		}
+	inf, getBlockErr := g.GetBlockInfo(height)
+	if getBlockErr != nil {
+		return nil, getBlockErr
+	}
 
-		client := *g.client
-		blockRes, getBlockErr := client.GetBlock(context.Background(), &pb.BlockRequest{Height: height})
-		if getBlockErr != nil {
-			return nil, getBlockErr
-		}
-		var b Block
-		toBlock(blockRes, &b)
+	return toBlock(inf)
+}
 
-		return &b, nil
-	*/
+//toBlock converts block info of node to Block
+func toBlock(inf *BlockInfo) (*Block, error) {
+	blockTime, err := time.Parse(time.RFC3339Nano, inf.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Block
+	b.Height = inf.Height
+	b.Hash = inf.BlockHash
+	b.ChainID = inf.ChainID
+	b.Time = blockTime
+	b.TxCounts = inf.NumTxs
+	b.TotalTxs = inf.TotalTxs
+	b.LastBlockHash = inf.LastBlockHash
+	b.LastCommitHash = inf.LastCommitHash
+	b.DataHash = inf.DataHash
+	b.ValidatorsHash = inf.ValidatorsHash
+	b.NextValidatorsHash = inf.NextValidatorsHash
+	b.ConsensusHash = inf.ConsensusHash
+	b.AppHash = inf.AppHash
+	b.LastResultsHash = inf.LastResultsHash
+	b.EvidenceHash = inf.EvidenceHash
+	b.ProposerAddress = inf.ProposerAddress
+	return &b, nil
+}
+
+//accountDetails is account state in responses of node
+type accountDetails struct {
+	Address     string                 `json:"Address"`
+	Balance     uint64                 `json:"Balance"`
+	Sequence    uint64                 `json:"Sequence"`
+	EVMCode     string                 `json:"EVMCode"`
+	Permissions map[string]interface{} `json:"Permissions"`
+}
+
+//toAccount converts account state of node to Account
+func toAccount(details *accountDetails) *Account {
+	var acc Account
+	acc.Address = strings.ToUpper(details.Address)
+	acc.Balance = details.Balance
+	acc.Sequence = details.Sequence
+	acc.Code = details.EVMCode
+	if details.Permissions != nil {
+		perms, _ := json.Marshal(details.Permissions)
+		acc.Permission = string(perms)
+	}
+	return &acc
 }
 
 //GetAccountsCount returns number of accounts
 func (g *Burrow) GetAccountsCount() int {
-	return 0
-	/*
-		l := len(g.accounts.Accounts)
-		return l
-	*/
+	accs, err := g.GetAccounts()
+	if err != nil {
+		return 0
+	}
+	return len(accs)
 }
 
-//GetAccount returns specified account
-func (g *Burrow) GetAccount(id int) (*Account, error) {
-	return nil, nil
-	/*
-		acc := g.accounts.Accounts[id].Account
-		ID := uint64(id)
-		var retAcc Account
-		toAccount(acc, &retAcc)
-		retAcc.ID = ID
-		return &retAcc, nil
-	*/
+//GetAccount returns state of account with given address
+func (g *Burrow) GetAccount(address string) (*Account, error) {
+
+	reqURL := g.Domain + "/account?address=" + url.QueryEscape(address)
+
+	responseData := g.GetReply(reqURL)
+
+	type Result struct {
+		Account *accountDetails `json:"Account"`
+	}
+
+	type AccountResponse struct {
+		Jsonrpc string `json:"jsonrpc"`
+		ID      string `json:"id"`
+		Result  Result `json:"result"`
+	}
+
+	var env AccountResponse
+	bytes := []byte(string(responseData))
+	if err := json.Unmarshal(bytes, &env); err != nil {
+		println("error on get account response: ", err.Error())
+		return nil, err
+	}
+
+	if env.Result.Account == nil {
+		return nil, fmt.Errorf("account %s does not exist", address)
+	}
+
+	return toAccount(env.Result.Account), nil
 }
 
 //GetAccounts returns all accounts in array of accounts
 func (g *Burrow) GetAccounts() ([]*Account, error) {
-	return nil, nil
-	/*
-		l := len(g.accounts.Accounts)
 
-		retAccounts := make([]*Account, l)
+	url := g.Domain + "/accounts"
+
+	responseData := g.GetReply(url)
+
+	type Result struct {
+		BlockHeight uint64            `json:"BlockHeight"`
+		Accounts    []*accountDetails `json:"Accounts"`
+	}
+
+	type AccountsResponse struct {
+		Jsonrpc string `json:"jsonrpc"`
+		ID      string `json:"id"`
+		Result  Result `json:"result"`
+	}
+
+	var env AccountsResponse
+	bytes := []byte(string(responseData))
+	if err := json.Unmarshal(bytes, &env); err != nil {
+		println("error on get accounts response: ", err.Error())
+		return nil, err
+	}
 
-		for i := 0; i < l; i++ {
-			acc := g.accounts.Accounts[i].Account
-			ID := uint64(i)
-			toAccount(acc, retAccounts[i])
-			retAccounts[i].ID = ID
+	accs := make([]*Account, 0, len(env.Result.Accounts))
+	for _, details := range env.Result.Accounts {
+		if details == nil {
+			continue
 		}
+		accs = append(accs, toAccount(details))
+	}
 
-		return retAccounts, nil
-	*/
+	return accs, nil
 }
 
-//GetBlocksInfo returns a group of blocks for faster access them
+//GetBlocksInfo returns full details of a group of blocks with signers of their
+//last commit, blocks are requested one by one
 func (g *Burrow) GetBlocksInfo(from uint64, to uint64) ([]BlockInfo, error) {
-	return nil, nil
-	/*
-		client := *g.client
-		blocks, getBlocksErr := client.GetBlocks(context.Background(), &pb.BlocksRequest{MinHeight: from, MaxHeight: to})
-		if getBlocksErr != nil {
-			return nil, getBlocksErr
-		}
+	if from > to {
+		return nil, fmt.Errorf("invalid range of blocks %d to %d", from, to)
+	}
 
-		n := len(blocks.GetBlocks())
-		retBlocks := make([]*BlockInfo, 0, n)
-		for i := 0; i < n; i++ {
-			toBlockInfo(blocks.GetBlocks()[i].GetHeader(), retBlocks[i])
+	blocks := make([]BlockInfo, 0, to-from+1)
+	for height := from; height <= to; height++ {
+		inf, err := g.GetBlockInfo(height)
+		if err != nil {
+			return nil, err
 		}
+		blocks = append(blocks, *inf)
+	}
 
-		return retBlocks, nil
-	*/
+	return blocks, nil
 }
 
 //GetBlocks returns a group of blocks for faster access them
@@ -398,34 +455,7 @@ func (g *Burrow) GetBlocks(from uint64, to uint64) ([]BlockInfo, error) {
 
 	for _, meta := range env.Result.BlockMetas {
 
-		heightStr := fmt.Sprintf("%v", meta.Header["height"])
-		blockHeight, _ := strconv.ParseInt(heightStr, 10, 64)
-
-		numTxsStr := fmt.Sprintf("%v", meta.Header["num_txs"])
-		numTxs, _ := strconv.ParseInt(numTxsStr, 10, 64)
-
-		totalTxsStr := fmt.Sprintf("%v", meta.Header["total_txs"])
-		totalTxs, _ := strconv.ParseInt(totalTxsStr, 10, 64)
-
-		var inf BlockInfo
-
-		//inf.VersionBlock = 0 //fmt.Sprintf("%v", env.Result.Block.Header["version"])
-		//inf.VersionApp = 0
-		inf.ChainID = fmt.Sprintf("%v", meta.Header["chain_id"])
-		inf.Height = blockHeight
-		inf.Time = fmt.Sprintf("%v", meta.Header["time"])
-		inf.NumTxs = numTxs
-		inf.TotalTxs = totalTxs
-		inf.BlockHash = fmt.Sprintf("%v", meta.ID.Hash)
-		//inf.LastBlockHash = "" //fmt.Sprintf("%v", meta.Header[""])
-		inf.DataHash = fmt.Sprintf("%v", meta.Header["data_hash"])
-		inf.ValidatorsHash = fmt.Sprintf("%v", meta.Header["validators_hash"])
-		inf.NextValidatorsHash = fmt.Sprintf("%v", meta.Header["next_validators_hash"])
-		inf.ConsensusHash = fmt.Sprintf("%v", meta.Header["consensus_hash"])
-		inf.AppHash = fmt.Sprintf("%v", meta.Header["app_hash"])
-		inf.LastResultsHash = fmt.Sprintf("%v", meta.Header["last_results_hash"])
-		inf.EvidenceHash = fmt.Sprintf("%v", meta.Header["evidence_hash"])
-		inf.ProposerAddress = fmt.Sprintf("%v", meta.Header["proposer_address"])
+		inf := blockInfoFromHeader(meta.ID.Hash, meta.Header)
 
 		//blocks[i] = &inf
 		blocks = append(blocks, inf)
@@ -434,6 +464,76 @@ func (g *Burrow) GetBlocks(from uint64, to uint64) ([]BlockInfo, error) {
 	return blocks, nil
 }
 
+//blockInfoFromHeader returns block info of a block header, fields that are missed in header are left empty
+func blockInfoFromHeader(hash string, header map[string]interface{}) BlockInfo {
+	blockHeight, _ := strconv.ParseInt(headerString(header, "height"), 10, 64)
+	numTxs, _ := strconv.ParseInt(headerString(header, "num_txs"), 10, 64)
+	totalTxs, _ := strconv.ParseInt(headerString(header, "total_txs"), 10, 64)
+
+	var inf BlockInfo
+
+	//inf.VersionBlock = 0 //fmt.Sprintf("%v", header["version"])
+	//inf.VersionApp = 0
+	inf.ChainID = headerString(header, "chain_id")
+	inf.Height = blockHeight
+	inf.Time = headerString(header, "time")
+	inf.NumTxs = numTxs
+	inf.TotalTxs = totalTxs
+	inf.BlockHash = hash
+	inf.LastBlockHash = lastBlockHash(header)
+	inf.LastCommitHash = headerString(header, "last_commit_hash")
+	inf.DataHash = headerString(header, "data_hash")
+	inf.ValidatorsHash = headerString(header, "validators_hash")
+	inf.NextValidatorsHash = headerString(header, "next_validators_hash")
+	inf.ConsensusHash = headerString(header, "consensus_hash")
+	inf.AppHash = headerString(header, "app_hash")
+	inf.LastResultsHash = headerString(header, "last_results_hash")
+	inf.EvidenceHash = headerString(header, "evidence_hash")
+	inf.ProposerAddress = headerString(header, "proposer_address")
+	return inf
+}
+
+//headerString returns a field of block header as string, it is empty if field is missed or null
+func headerString(header map[string]interface{}, key string) string {
+	value, ok := header[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+//lastBlockHash returns hash of previous block from block header
+func lastBlockHash(header map[string]interface{}) string {
+	lastBlockID, ok := header["last_block_id"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	hash, _ := lastBlockID["hash"].(string)
+	return hash
+}
+
+//commitSigners returns addresses of validators that signed last commit of block and number of
+//validators in set of commit, absent validators have a null precommit
+func commitSigners(lastCommit map[string]interface{}) ([]string, int) {
+	signers := make([]string, 0)
+	precommits, ok := lastCommit["precommits"].([]interface{})
+	if !ok {
+		return signers, 0
+	}
+
+	for _, precommit := range precommits {
+		vote, ok := precommit.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		address, _ := vote["validator_address"].(string)
+		if address != "" {
+			signers = append(signers, strings.ToUpper(address))
+		}
+	}
+	return signers, len(precommits)
+}
+
 /*
 type BlockTxsResponse struct {
 	Count                int32                                         `protobuf:"varint,1,opt,name=Count,proto3" json:"Count,omitempty"`
@@ -504,42 +604,139 @@ type TxInput struct {
 
 //GetTXsCount returns number of TXs
 func (g *Burrow) GetTXsCount(height uint64) int {
-	return 0
-
-	/*
-		client := *g.client
-		txs, _ := client.GetBlockTxs(context.Background(), &pb.BlockRequest{Height: height})
-		n := int(txs.Count)
-		return n
-	*/
+	inf, err := g.GetBlockInfo(height)
+	if err != nil {
+		return 0
+	}
+	return int(inf.NumTxs)
 }
 
-//GetTx returns specified TX
+//GetTx returns specified TX by searching txs of its block
 func (g *Burrow) GetTx(height uint64, hash []byte) (*Transaction, error) {
-	return nil, nil
-	/*
-		client := *g.client
+	txs, err := g.GetTXs(height)
+	if err != nil {
+		return nil, err
+	}
 
-		blockRes, getBlockErr := client.GetBlock(context.Background(), &pb.BlockRequest{Height: height})
-		if getBlockErr != nil {
-			return nil, getBlockErr
+	findHash := hex.EncodeToString(hash)
+	for i := range txs {
+		if strings.EqualFold(txs[i].Hash, findHash) {
+			return &txs[i], nil
 		}
-		var b Block
-		toBlock(blockRes, &b)
+	}
 
-		findHash := hex.EncodeToString(hash)
-		txRes, getTxErr := client.GetTx(context.Background(), &pb.TxRequest{TxHash: findHash})
-		if getTxErr != nil {
-			return nil, getTxErr
-		}
+	return nil, fmt.Errorf("tx %s does not exist in block %d", findHash, height)
+}
+
+//ErrTxLookupNotServed is returned when node does not serve /tx?hash=, it is not a route of stock Burrow nodes
+var ErrTxLookupNotServed = errors.New("node does not serve /tx?hash=")
+
+//SupportsTxLookup checks whether node serves lookup of txs by hash. Node does not serve it only
+//when it does not have the route, a request that fails is returned as an error
+func (g *Burrow) SupportsTxLookup() (bool, error) {
+	reqURL := g.Domain + "/tx?hash=" + url.QueryEscape(strings.Repeat("0", 64))
+
+	responseData, status, err := g.getResponse(reqURL)
+	if err != nil {
+		return false, fmt.Errorf("error on checking tx lookup of node: %w", err)
+	}
+	return !lacksRoute(status, responseData), nil
+}
+
+//GetTxByHash returns a TX by its hash from /tx endpoint of node, it returns ErrTxLookupNotServed
+//on nodes that do not serve this endpoint
+func (g *Burrow) GetTxByHash(hash []byte) (*Transaction, error) {
+
+	findHash := strings.ToUpper(hex.EncodeToString(hash))
+	reqURL := g.Domain + "/tx?hash=" + url.QueryEscape(findHash)
+
+	responseData, status, err := g.getResponse(reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("error on get tx %s: %w", findHash, err)
+	}
+	if lacksRoute(status, responseData) {
+		return nil, ErrTxLookupNotServed
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("error on get tx %s, status code: %v", findHash, status)
+	}
+
+	type TxResponse struct {
+		Jsonrpc string   `json:"jsonrpc"`
+		ID      string   `json:"id"`
+		Result  burrowTx `json:"result"`
+	}
+
+	var env TxResponse
+	bytes := []byte(string(responseData))
+	if err := json.Unmarshal(bytes, &env); err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(env.Result.Data.Hash, findHash) {
+		return nil, fmt.Errorf("tx %s does not exist", findHash)
+	}
+
+	var tx Transaction
+	if err := toTransaction(env.Result, env.Result.Data.Height, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+//burrowTxDetails is a tx inside an envelope
+type burrowTxDetails struct {
+	ChainID string                 `json:"ChainID"`
+	Type    string                 `json:"Type"`
+	Payload map[string]interface{} `json:"Payload"`
+}
+
+//burrowEnvelope is a signed tx
+type burrowEnvelope struct {
+	Signatories []map[string]interface{} `json:"Signatories"`
+	Tx          burrowTxDetails          `json:"Tx"`
+}
+
+//burrowTxData is a tx that is returned by node, envelope is its json
+type burrowTxData struct {
+	Height   uint64      `json:"Height"`
+	Hash     string      `json:"Hash"`
+	ChainID  string      `json:"ChainID"`
+	Payload  interface{} `json:"Payload"`
+	Envelope string      `json:"Envelope"`
+}
 
-		var tx Transaction
-		toTx(txRes, &tx)
-		tx.Time = b.Time
-		tx.BlockID = int64(height)
+type burrowTx struct {
+	Hash string       `json:"Hash"`
+	Data burrowTxData `json:"Data"`
+}
 
-		return &tx, nil
+//toTransaction decodes envelope of a tx that is returned by node into tx
+func toTransaction(btx burrowTx, height uint64, tx *Transaction) error {
+	envStr := btx.Data.Envelope
+	/*
+		envStr = strings.ReplaceAll(envStr, "\n", "")
+		envStr = strings.ReplaceAll(envStr, "\\\"", "\"")
+		envStr = strings.ReplaceAll(envStr, "\\\\", "\\")
 	*/
+
+	//numbers are kept as json.Number so big amounts are not rounded
+	var objEnvelope burrowEnvelope
+	decoder := json.NewDecoder(strings.NewReader(envStr))
+	decoder.UseNumber()
+	if err := decoder.Decode(&objEnvelope); err != nil {
+		return err
+	}
+
+	tx.Type = objEnvelope.Tx.Type
+	tx.BlockID = int64(height)
+	tx.Hash = btx.Data.Hash
+
+	if err := decodeTx(objEnvelope.Tx.Type, objEnvelope.Tx.Payload, tx); err != nil {
+		println(err.Error())
+		return err
+	}
+	return nil
 }
 
 //GetTXs returns all transaction of specific block
@@ -561,62 +758,9 @@ func (g *Burrow) GetTXs(height uint64) ([]Transaction, error) {
 
 	responseData := g.GetReply(url)
 
-	type InputDetails struct {
-		Address  string `json:"Address"`
-		Amount   uint64 `json:"Amount"`
-		Sequence uint64 `json:"Sequence"`
-	}
-
-	type OutputDetails struct {
-		Address string `json:"Address"`
-		Amount  uint64 `json:"Amount"`
-	}
-
-	type SendTxPayLoad struct {
-		Inputs       []InputDetails           `json:"Inputs"`
-		Outputs      []OutputDetails          `json:"Outputs"`
-		GasLimit     uint64                   `json:"GasLimit"`
-		Fee          uint64                   `json:"Fee"`
-		Data         string                   `json:"Data"`
-		ContractMeta []map[string]interface{} `json:"ContractMeta"`
-	}
-
-	type CallTxPayLoad struct {
-		Input        InputDetails             `json:"Input"`
-		Address      string                   `json:"Address"`
-		GasLimit     uint64                   `json:"GasLimit"`
-		Fee          uint64                   `json:"Fee"`
-		Data         string                   `json:"Data"`
-		ContractMeta []map[string]interface{} `json:"ContractMeta"`
-	}
-
-	type TxDetails struct {
-		ChainID string                 `json:"ChainID"`
-		Type    string                 `json:"Type"`
-		Payload map[string]interface{} `json:"Payload"`
-	}
-
-	type EnvelopeData struct {
-		Signatories []map[string]interface{} `json:"Signatories"`
-		Tx          TxDetails                `json:"Tx"`
-	}
-
-	type TxData struct {
-		Height   uint64      `json:"Height"`
-		Hash     string      `json:"Hash"`
-		ChainID  string      `json:"ChainID"`
-		Payload  interface{} `json:"Payload"`
-		Envelope string      `json:"Envelope"`
-	}
-
-	type Tx struct {
-		Hash string `json:"Hash"`
-		Data TxData `json:"Data"`
-	}
-
 	type Result struct {
-		Count uint64 `json:"Count"`
-		Txs   []Tx   `json:"Txs"`
+		Count uint64     `json:"Count"`
+		Txs   []burrowTx `json:"Txs"`
 	}
 
 	type BlockTxs struct {
@@ -641,153 +785,215 @@ func (g *Burrow) GetTXs(height uint64) ([]Transaction, error) {
 	txs := make([]Transaction, nTxs)
 
 	for i, tx := range env.Result.Txs {
-
-		envStr := tx.Data.Envelope
-		/*
-			envStr = strings.ReplaceAll(envStr, "\n", "")
-			envStr = strings.ReplaceAll(envStr, "\\\"", "\"")
-			envStr = strings.ReplaceAll(envStr, "\\\\", "\\")
-		*/
-
-		var objEnvelope EnvelopeData
-		bytes := []byte(string(envStr))
-		if err := json.Unmarshal(bytes, &objEnvelope); err != nil {
+		if err := toTransaction(tx, height, &txs[i]); err != nil {
 			return nil, err
 		}
+	}
 
-		txs[i].Type = objEnvelope.Tx.Type
-		txs[i].BlockID = int64(height)
-		txs[i].Hash = tx.Data.Hash
+	return txs, nil
 
-		if objEnvelope.Tx.Type == "CallTx" {
+}
 
-			pl := objEnvelope.Tx.Payload
+//rpcMethodNotFound is json rpc error code of a method that node does not have
+const rpcMethodNotFound = -32601
 
-			input := pl["Input"]
-			objInput, ok := input.(map[string]interface{})
-			if !ok {
-				println("error on converting inputs: ", ok)
-				return nil, fmt.Errorf("error on converting inputs")
-			}
+//lacksRoute checks whether reply with status and body means node does not have requested route, that is
+//404 or method not found error of json rpc
+func lacksRoute(status int, body string) bool {
+	if status == http.StatusNotFound {
+		return true
+	}
 
-			Gaslimit := uint64(0)
-			if pl["GasLimit"] != nil {
-				gaslimitStr := fmt.Sprintf("%v", pl["GasLimit"])
-				Gaslimit, _ = strconv.ParseUint(gaslimitStr, 10, 64)
-			}
+	type RPCError struct {
+		Code int `json:"code"`
+	}
+	type Reply struct {
+		Error *RPCError `json:"error"`
+	}
 
-			Fee := uint64(0)
-			if pl["Fee"] != nil {
-				feeStr := fmt.Sprintf("%v", pl["Fee"])
-				Fee, _ = strconv.ParseUint(feeStr, 10, 64)
-			}
+	var env Reply
+	if err := json.Unmarshal([]byte(body), &env); err != nil {
+		return false
+	}
+	return env.Error != nil && env.Error.Code == rpcMethodNotFound
+}
 
-			data := ""
-			if pl["Data"] != nil {
-				data = pl["Data"].(string)
-			}
+//SupportsTxExecutions checks whether node serves execution results of transactions. Node does not serve
+//them only when it does not have the route, a request that fails is returned as an error
+func (g *Burrow) SupportsTxExecutions() (bool, error) {
+	height, err := g.GetBlocksLastHeight()
+	if err != nil {
+		return false, err
+	}
 
-			toAddr := ""
-			if pl["Address"] != nil {
-				toAddr = pl["Address"].(string)
-			}
+	reqURL := g.Domain + "/tx_executions?height=" + url.QueryEscape(strconv.FormatUint(height, 10))
 
-			amount := uint64(0)
-			if objInput["Amount"] != nil {
-				amountStr := fmt.Sprintf("%v", objInput["Amount"])
-				amount, _ = strconv.ParseUint(amountStr, 10, 64)
-			}
+	responseData, status, err := g.getResponse(reqURL)
+	if err != nil {
+		return false, fmt.Errorf("error on checking tx executions of node: %w", err)
+	}
+	if lacksRoute(status, responseData) {
+		return false, nil
+	}
+	if status != http.StatusOK {
+		return false, fmt.Errorf("error on checking tx executions of node, status code: %v", status)
+	}
 
-			seq := uint64(0)
-			if objInput["Sequence"] != nil {
-				seqStr := fmt.Sprintf("%v", objInput["Sequence"])
-				seq, _ = strconv.ParseUint(seqStr, 10, 64)
-			}
+	type BlockExecutions struct {
+		Result *json.RawMessage `json:"result"`
+	}
 
-			txs[i].GasLimit = Gaslimit
-			txs[i].Fee = Fee
-			txs[i].Data = data
-			txs[i].From = objInput["Address"].(string)
-			txs[i].To = toAddr
-			txs[i].Amount = amount
-			txs[i].Sequence = seq
+	var env BlockExecutions
+	if err := json.Unmarshal([]byte(responseData), &env); err != nil {
+		return false, fmt.Errorf("error on checking tx executions of node and unmarshaling response: %w", err)
+	}
+	if env.Result == nil {
+		return false, fmt.Errorf("error on checking tx executions of node, reply has no result: %v", responseData)
+	}
+	return true, nil
+}
 
-		} else if objEnvelope.Tx.Type == "SendTx" {
+//GetTxExecutions returns execution results and events of all transactions of specific block
+func (g *Burrow) GetTxExecutions(height uint64) ([]TxExecution, error) {
 
-			pl := objEnvelope.Tx.Payload
+	reqURL := g.Domain + "/tx_executions?height=" + url.QueryEscape(strconv.FormatUint(height, 10))
 
-			Inputs := pl["Inputs"]
-			objInputs, ok := Inputs.([]interface{}) //map[string]interface{})
-			if !ok {
-				println("error on converting inputs: ", ok)
-				return nil, fmt.Errorf("error on converting inputs")
-			}
+	responseData := g.GetReply(reqURL)
 
-			fromAddr := ""
-			toAddr := ""
-			amount := uint64(0)
-			seq := uint64(0)
-
-			for i, vi := range objInputs {
-				if i == 0 {
-					Input, ok := vi.(map[string]interface{})
-					if !ok {
-						println("error on converting inputs: ", ok)
-						return nil, fmt.Errorf("error on converting inputs")
-					}
-					fromAddr = Input["Address"].(string)
-
-					if Input["Amount"] != nil {
-						amountStr := fmt.Sprintf("%v", Input["Amount"])
-						amount, _ = strconv.ParseUint(amountStr, 10, 64)
-					}
-
-					if Input["Sequence"] != nil {
-						seqStr := fmt.Sprintf("%v", Input["Sequence"])
-						seq, _ = strconv.ParseUint(seqStr, 10, 64)
-					}
-				}
-			}
+	type EventHeader struct {
+		EventType string      `json:"EventType"`
+		EventID   string      `json:"EventID"`
+		Index     json.Number `json:"Index"`
+	}
+
+	type LogEvent struct {
+		Address string   `json:"Address"`
+		Data    string   `json:"Data"`
+		Topics  []string `json:"Topics"`
+	}
+
+	type Event struct {
+		Header        EventHeader            `json:"Header"`
+		Input         map[string]interface{} `json:"Input"`
+		Output        map[string]interface{} `json:"Output"`
+		Call          map[string]interface{} `json:"Call"`
+		Log           *LogEvent              `json:"Log"`
+		GovernAccount map[string]interface{} `json:"GovernAccount"`
+	}
+
+	type TxHeader struct {
+		TxType string      `json:"TxType"`
+		TxHash string      `json:"TxHash"`
+		Height json.Number `json:"Height"`
+		Index  json.Number `json:"Index"`
+	}
+
+	type ExecResult struct {
+		Return  string      `json:"Return"`
+		GasUsed json.Number `json:"GasUsed"`
+	}
 
-			Outputs := pl["Outputs"]
-			objOutputs, ok := Outputs.([]interface{})
-			if !ok {
-				println("error on converting outputs: ", ok)
-				return nil, fmt.Errorf("error on converting outputs")
+	type Receipt struct {
+		CreatesContract bool   `json:"CreatesContract"`
+		ContractAddress string `json:"ContractAddress"`
+	}
+
+	type Exception struct {
+		Code      json.Number `json:"Code"`
+		Exception string      `json:"Exception"`
+	}
+
+	type Execution struct {
+		TxHeader  TxHeader    `json:"TxHeader"`
+		Events    []Event     `json:"Events"`
+		Result    *ExecResult `json:"Result"`
+		Receipt   *Receipt    `json:"Receipt"`
+		Exception *Exception  `json:"Exception"`
+	}
+
+	type Result struct {
+		Height       uint64      `json:"Height"`
+		TxExecutions []Execution `json:"TxExecutions"`
+	}
+
+	type BlockExecutions struct {
+		Jsonrpc string `json:"jsonrpc"`
+		ID      string `json:"id"`
+		Result  Result `json:"result"`
+	}
+
+	var env BlockExecutions
+	decoder := json.NewDecoder(strings.NewReader(responseData))
+	decoder.UseNumber()
+	if err := decoder.Decode(&env); err != nil {
+		println("error on get tx executions of block and unmarshaling response: ", err.Error())
+		return nil, err
+	}
+
+	execs := make([]TxExecution, 0, len(env.Result.TxExecutions))
+	for _, e := range env.Result.TxExecutions {
+		var exec TxExecution
+		exec.TxHash = e.TxHeader.TxHash
+		//executions are filed at requested height, header of a malformed execution may have no height
+		exec.Height = int64(height)
+		exec.Index, _ = e.TxHeader.Index.Int64()
+		exec.Succeeded = e.Exception == nil
+
+		if e.Result != nil {
+			exec.Return = e.Result.Return
+			gasUsed, _ := e.Result.GasUsed.Int64()
+			exec.GasUsed = uint64(gasUsed)
+		}
+		if e.Receipt != nil {
+			exec.CreatesContract = e.Receipt.CreatesContract
+			exec.ContractAddress = strings.ToUpper(e.Receipt.ContractAddress)
+		}
+		if e.Exception != nil {
+			exec.ExceptionCode, _ = e.Exception.Code.Int64()
+			exec.Exception = e.Exception.Exception
+		}
+
+		exec.Events = make([]TxEvent, 0, len(e.Events))
+		for i, ev := range e.Events {
+			var event TxEvent
+			event.Index = int64(i)
+			event.EventType = ev.Header.EventType
+			event.EventID = ev.Header.EventID
+
+			var body interface{}
+			switch {
+			case ev.Log != nil:
+				body = ev.Log
+				event.Address = strings.ToUpper(ev.Log.Address)
+				event.Topics = ev.Log.Topics
+				event.LogData = ev.Log.Data
+			case ev.Call != nil:
+				body = ev.Call
+			case ev.Input != nil:
+				body = ev.Input
+			case ev.Output != nil:
+				body = ev.Output
+			case ev.GovernAccount != nil:
+				body = ev.GovernAccount
 			}
-			for i, vi := range objOutputs {
-				if i == 0 {
-					Output, ok := vi.(map[string]interface{})
-					if !ok {
-						println("error on converting outputs: ", ok)
-						return nil, fmt.Errorf("error on converting outputs")
-					}
-					toAddr = Output["Address"].(string)
-				}
+			if body != nil {
+				data, _ := json.Marshal(body)
+				event.Data = string(data)
 			}
 
-			txs[i].From = fromAddr
-			txs[i].To = toAddr
-			txs[i].Amount = amount
-			txs[i].Sequence = seq
-
-		} else {
-			txs[i].From = ""
-			txs[i].To = ""
-			txs[i].Amount = 0
-			txs[i].Sequence = 0
+			exec.Events = append(exec.Events, event)
 		}
 
+		execs = append(execs, exec)
 	}
 
-	return txs, nil
-
+	return execs, nil
 }
 
 //GetNodes returns all nodes status
 func (g *Burrow) GetNodes() ([]Peer, error) {
 
-	url := fmt.Sprintf(g.Domain + "/network")
+	url := g.Domain + "/network"
 
 	responseData := g.GetReply(url)
 
@@ -819,7 +1025,7 @@ func (g *Burrow) GetNodes() ([]Peer, error) {
 //GetSyncInfo returns sync status of network
 func (g *Burrow) GetSyncInfo() (*StatusSyncInfo, error) {
 
-	url := fmt.Sprintf(g.Domain + "/status")
+	url := g.Domain + "/status"
 
 	responseData := g.GetReply(url)
 
@@ -850,3 +1056,120 @@ func (g *Burrow) GetSyncInfo() (*StatusSyncInfo, error) {
 
 	return &env.Result.SyncInfo, nil
 }
+
+//GetValidators returns bonded validators of network at latest height
+func (g *Burrow) GetValidators() (*ValidatorSet, error) {
+
+	url := g.Domain + "/validators"
+
+	responseData := g.GetReply(url)
+
+	type ValidatorDetails struct {
+		Address   string                 `json:"Address"`
+		PublicKey map[string]interface{} `json:"PublicKey"`
+		Power     uint64                 `json:"Power"`
+	}
+
+	type Validators struct {
+		BlockHeight         uint64             `json:"BlockHeight"`
+		BondedValidators    []ValidatorDetails `json:"BondedValidators"`
+		UnbondingValidators []ValidatorDetails `json:"UnbondingValidators"`
+	}
+
+	type ValidatorsResponse struct {
+		Jsonrpc string     `json:"jsonrpc"`
+		ID      string     `json:"id"`
+		Result  Validators `json:"result"`
+	}
+
+	var env ValidatorsResponse
+	bytes := []byte(string(responseData))
+	if err := json.Unmarshal(bytes, &env); err != nil {
+		println("error on get validators response: ", err.Error())
+		return nil, err
+	}
+
+	var set ValidatorSet
+	set.Height = int64(env.Result.BlockHeight)
+	set.Validators = make([]Validator, 0, len(env.Result.BondedValidators))
+	for _, val := range env.Result.BondedValidators {
+		var v Validator
+		v.Address = strings.ToUpper(val.Address)
+		if val.PublicKey != nil {
+			v.PublicKey = fmt.Sprintf("%v", val.PublicKey["PublicKey"])
+		}
+		v.Power = val.Power
+		set.Validators = append(set.Validators, v)
+	}
+
+	return &set, nil
+}
+
+//GetGenesis returns genesis document of chain, power of genesis validators is their bonded amount
+//and balance of genesis accounts is their amount
+func (g *Burrow) GetGenesis() (*Genesis, error) {
+
+	url := g.Domain + "/genesis"
+
+	responseData := g.GetReply(url)
+
+	type GenesisValidator struct {
+		Address   string                 `json:"Address"`
+		PublicKey map[string]interface{} `json:"PublicKey"`
+		Amount    uint64                 `json:"Amount"`
+		Name      string                 `json:"Name"`
+	}
+
+	type GenesisAccount struct {
+		Address string `json:"Address"`
+		Amount  uint64 `json:"Amount"`
+	}
+
+	type GenesisDoc struct {
+		ChainName  string             `json:"ChainName"`
+		Validators []GenesisValidator `json:"Validators"`
+		Accounts   []GenesisAccount   `json:"Accounts"`
+	}
+
+	type Result struct {
+		Genesis GenesisDoc `json:"Genesis"`
+	}
+
+	type GenesisResponse struct {
+		Jsonrpc string `json:"jsonrpc"`
+		ID      string `json:"id"`
+		Result  Result `json:"result"`
+	}
+
+	var env GenesisResponse
+	bytes := []byte(string(responseData))
+	if err := json.Unmarshal(bytes, &env); err != nil {
+		println("error on get genesis response: ", err.Error())
+		return nil, err
+	}
+
+	doc := env.Result.Genesis
+	if len(doc.Validators) == 0 {
+		return nil, fmt.Errorf("genesis of chain has no validators")
+	}
+
+	var genesis Genesis
+	genesis.ChainName = doc.ChainName
+	genesis.Validators = make([]Validator, 0, len(doc.Validators))
+	for _, val := range doc.Validators {
+		var v Validator
+		v.Address = strings.ToUpper(val.Address)
+		if val.PublicKey != nil {
+			v.PublicKey = fmt.Sprintf("%v", val.PublicKey["PublicKey"])
+		}
+		v.Power = val.Amount
+		genesis.Validators = append(genesis.Validators, v)
+	}
+
+	genesis.Accounts = make([]Account, 0, len(doc.Accounts))
+	for _, acc := range doc.Accounts {
+		genesis.Accounts = append(genesis.Accounts, Account{Address: strings.ToUpper(acc.Address), Balance: acc.Amount})
+	}
+
+	return &genesis, nil
+}
@@ -0,0 +1,161 @@
+package blockchain
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	config "github.com/BurrowBlocks/config"
+	"github.com/stretchr/testify/require"
+)
+
+//newTestBurrow returns a client of a node that replies to each path with its json in replies
+func newTestBurrow(t *testing.T, replies map[string]string) *Burrow {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply, ok := replies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(srv.Close)
+
+	g := &Burrow{Config: config.DefaultConfig()}
+	require.NoError(t, g.CreateClient())
+	g.Domain = srv.URL
+	return g
+}
+
+func TestGetBlocksMissingHeaderFields(t *testing.T) {
+	g := newTestBurrow(t, map[string]string{"/blocks": `{"result": {"LastHeight": 8, "BlockMetas": [
+		{"block_id": {"hash": "H7"}, "header": {"chain_id": "C1", "height": "7", "time": "2020-01-01T00:00:00Z",
+			"num_txs": "2", "total_txs": "10", "last_block_id": {"hash": "H6"}, "last_commit_hash": "LC7",
+			"data_hash": "D7", "validators_hash": "V7", "next_validators_hash": "NV7", "consensus_hash": "CS7",
+			"app_hash": "A7", "last_results_hash": "LR7", "evidence_hash": "E7", "proposer_address": "P7"}},
+		{"block_id": {"hash": "H8"}, "header": {"chain_id": "C1", "height": "8", "time": "2020-01-01T00:00:01Z",
+			"num_txs": "0", "last_block_id": null, "data_hash": null, "evidence_hash": null, "proposer_address": null}}
+	]}}`})
+
+	blocks, err := g.GetBlocks(7, 8)
+	require.NoError(t, err)
+	require.Equal(t, []BlockInfo{
+		{BlockHash: "H7", ChainID: "C1", Height: 7, Time: "2020-01-01T00:00:00Z", NumTxs: 2, TotalTxs: 10,
+			LastBlockHash: "H6", LastCommitHash: "LC7", DataHash: "D7", ValidatorsHash: "V7", NextValidatorsHash: "NV7",
+			ConsensusHash: "CS7", AppHash: "A7", LastResultsHash: "LR7", EvidenceHash: "E7", ProposerAddress: "P7"},
+		//missed and null fields are left empty instead of being saved as "<nil>"
+		{BlockHash: "H8", ChainID: "C1", Height: 8, Time: "2020-01-01T00:00:01Z"},
+	}, blocks)
+}
+
+func TestGetBlockInfoMissingHeaderFields(t *testing.T) {
+	g := newTestBurrow(t, map[string]string{"/block": `{"result": {
+		"BlockMeta": {"block_id": {"hash": "H9"}},
+		"Block": {"header": {"chain_id": "C1", "height": "9", "time": "2020-01-01T00:00:02Z", "num_txs": "1",
+			"validators_hash": null, "app_hash": "A9"},
+			"last_commit": {"precommits": [{"validator_address": "v1"}, null, {"validator_address": "v3"}]}}
+	}}`})
+
+	inf, err := g.GetBlockInfo(9)
+	require.NoError(t, err)
+	require.Equal(t, &BlockInfo{BlockHash: "H9", ChainID: "C1", Height: 9, Time: "2020-01-01T00:00:02Z", NumTxs: 1,
+		AppHash: "A9", LastCommitSigners: []string{"V1", "V3"}, LastCommitSize: 3}, inf)
+
+	//a block without time can not be converted, instead of having a wrong time
+	_, err = toBlock(&BlockInfo{Height: 9})
+	require.Error(t, err)
+}
+
+func TestGetValidators(t *testing.T) {
+	g := newTestBurrow(t, map[string]string{"/validators": `{"result": {"BlockHeight": 12,
+		"BondedValidators": [{"Address": "v1", "PublicKey": {"CurveType": "ed25519", "PublicKey": "K1"}, "Power": 10},
+			{"Address": "v2", "PublicKey": null, "Power": 5}],
+		"UnbondingValidators": [{"Address": "v3", "Power": 0}]
+	}}`})
+
+	set, err := g.GetValidators()
+	require.NoError(t, err)
+	require.Equal(t, &ValidatorSet{Height: 12, Validators: []Validator{
+		{Address: "V1", PublicKey: "K1", Power: 10},
+		{Address: "V2", Power: 5},
+	}}, set)
+}
+
+func TestSupportsTxExecutions(t *testing.T) {
+	consensus := `{"result": {"round_state": {"height": "5"}}}`
+
+	g := newTestBurrow(t, map[string]string{"/consensus": consensus,
+		"/tx_executions": `{"result": {"Height": 4, "TxExecutions": []}}`})
+	supported, err := g.SupportsTxExecutions()
+	require.NoError(t, err)
+	require.True(t, supported)
+
+	//node lacks the route when it replies 404 or method not found
+	g = newTestBurrow(t, map[string]string{"/consensus": consensus})
+	supported, err = g.SupportsTxExecutions()
+	require.NoError(t, err)
+	require.False(t, supported)
+
+	g = newTestBurrow(t, map[string]string{"/consensus": consensus,
+		"/tx_executions": `{"error": {"code": -32601, "message": "Method not found"}}`})
+	supported, err = g.SupportsTxExecutions()
+	require.NoError(t, err)
+	require.False(t, supported)
+
+	//other errors of node are not taken as a node without executions
+	g = newTestBurrow(t, map[string]string{"/consensus": consensus,
+		"/tx_executions": `{"error": {"code": -32603, "message": "Internal error"}}`})
+	_, err = g.SupportsTxExecutions()
+	require.Error(t, err)
+
+	g = newTestBurrow(t, map[string]string{"/consensus": consensus, "/tx_executions": `<html>`})
+	_, err = g.SupportsTxExecutions()
+	require.Error(t, err)
+
+	//request to node that fails is an error
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tx_executions" {
+			panic(http.ErrAbortHandler)
+		}
+		w.Write([]byte(consensus))
+	}))
+	defer srv.Close()
+	g.Domain = srv.URL
+	_, err = g.SupportsTxExecutions()
+	require.Error(t, err)
+}
+
+func TestGetTxByHashOnStockNode(t *testing.T) {
+	hash := make([]byte, 32)
+
+	//stock nodes have no /tx route
+	g := newTestBurrow(t, map[string]string{})
+	supported, err := g.SupportsTxLookup()
+	require.NoError(t, err)
+	require.False(t, supported)
+	_, err = g.GetTxByHash(hash)
+	require.Equal(t, ErrTxLookupNotServed, err)
+
+	g = newTestBurrow(t, map[string]string{"/tx": `{"result": {"Hash": "", "Data": {}}}`})
+	supported, err = g.SupportsTxLookup()
+	require.NoError(t, err)
+	require.True(t, supported)
+	_, err = g.GetTxByHash(hash)
+	require.Error(t, err)
+	require.NotEqual(t, ErrTxLookupNotServed, err)
+}
+
+func TestQueryValuesAreEscaped(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"result": {"Account": null}}`))
+	}))
+	defer srv.Close()
+	g := newTestBurrow(t, nil)
+	g.Domain = srv.URL
+
+	_, err := g.GetAccount("A1&height=1")
+	require.Error(t, err)
+	require.Equal(t, "address=A1%26height%3D1", query)
+}
@@ -0,0 +1,54 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+//IsContractCreation checks whether tx deploys a contract, a CallTx without address runs its data as init code
+func IsContractCreation(tx *Transaction) bool {
+	return tx.Type == "CallTx" && tx.To == ""
+}
+
+//DeployedContracts returns contracts that are deployed by txs of a block. Address of contract is
+//read from execution of tx, txs whose execution failed or is unknown do not deploy any contract
+func DeployedContracts(txs []Transaction, execs []TxExecution) []Contract {
+	results := make(map[string]TxExecution)
+	for _, exec := range execs {
+		results[strings.ToUpper(exec.TxHash)] = exec
+	}
+
+	contracts := make([]Contract, 0)
+	for i := range txs {
+		tx := &txs[i]
+		if !IsContractCreation(tx) {
+			continue
+		}
+
+		exec, ok := results[strings.ToUpper(tx.Hash)]
+		if !ok || !exec.Succeeded || !exec.CreatesContract || exec.ContractAddress == "" {
+			continue
+		}
+		contracts = append(contracts, Contract{
+			Address: strings.ToUpper(exec.ContractAddress),
+			Creator: tx.From,
+			TxHash:  tx.Hash,
+			Height:  tx.BlockID,
+		})
+	}
+	return contracts
+}
+
+//CodeHash returns keccak256 of hex code of a contract or empty string for empty or invalid code
+func CodeHash(code string) string {
+	data, err := hex.DecodeString(code)
+	if err != nil || len(data) == 0 {
+		return ""
+	}
+
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(data)
+	return strings.ToUpper(hex.EncodeToString(hasher.Sum(nil)))
+}
@@ -0,0 +1,25 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployedContracts(t *testing.T) {
+	txs := []Transaction{
+		{Hash: "T1", BlockID: 3, Type: "CallTx", From: "A1"},
+		{Hash: "T2", BlockID: 3, Type: "CallTx", From: "A1"},
+		{Hash: "T3", BlockID: 3, Type: "CallTx", From: "A1"},
+		{Hash: "T4", BlockID: 3, Type: "CallTx", From: "A1", To: "C9"},
+	}
+	execs := []TxExecution{
+		{TxHash: "t1", Succeeded: true, CreatesContract: true, ContractAddress: "c1"},
+		{TxHash: "T2", Succeeded: false, CreatesContract: true, ContractAddress: "C2"},
+		{TxHash: "T4", Succeeded: true},
+	}
+
+	//T2 failed and outcome of T3 is unknown
+	require.Equal(t, []Contract{{Address: "C1", Creator: "A1", TxHash: "T1", Height: 3}}, DeployedContracts(txs, execs))
+	require.Empty(t, DeployedContracts(txs, nil))
+}
@@ -0,0 +1,241 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+//TxDecoder fills fields of tx from payload of its envelope
+type TxDecoder func(payload map[string]interface{}, tx *Transaction) error
+
+//txDecoders keeps decoder of each tx type
+var txDecoders = make(map[string]TxDecoder)
+
+//RegisterTxDecoder sets decoder of a tx type and replaces previous decoder of that type.
+//It is not safe to call it while blocks are fetched, so it should be called in init
+func RegisterTxDecoder(txType string, decoder TxDecoder) {
+	txDecoders[txType] = decoder
+}
+
+//decodeTx fills tx by decoder of its type, txs without a decoder only keep their type and hash
+func decodeTx(txType string, payload map[string]interface{}, tx *Transaction) error {
+	decoder, ok := txDecoders[txType]
+	if !ok {
+		return nil
+	}
+	if err := decoder(payload, tx); err != nil {
+		return fmt.Errorf("error on decoding %s %s: %s", txType, tx.Hash, err.Error())
+	}
+	return nil
+}
+
+func init() {
+	RegisterTxDecoder("CallTx", decodeCallTx)
+	RegisterTxDecoder("SendTx", decodeSendTx)
+	RegisterTxDecoder("NameTx", decodeNameTx)
+	RegisterTxDecoder("BondTx", decodeBondTx)
+	RegisterTxDecoder("UnbondTx", decodeUnbondTx)
+	RegisterTxDecoder("PermsTx", decodePermsTx)
+	RegisterTxDecoder("GovTx", decodeGovTx)
+	RegisterTxDecoder("ProposalTx", decodeProposalTx)
+	RegisterTxDecoder("BatchTx", decodeBatchTx)
+}
+
+func decodeCallTx(pl map[string]interface{}, tx *Transaction) error {
+	if err := setInput(pl["Input"], tx); err != nil {
+		return err
+	}
+
+	tx.GasLimit = uintField(pl, "GasLimit")
+	tx.Fee = uintField(pl, "Fee")
+	tx.Data = stringField(pl, "Data")
+	tx.To = stringField(pl, "Address")
+	//callee receives what is left of input after fee, the same as its balance change
+	if tx.To != "" {
+		tx.Outputs = []TxIO{{Address: tx.To, Amount: tx.Amount - txFee(tx)}}
+	}
+	return nil
+}
+
+func decodeSendTx(pl map[string]interface{}, tx *Transaction) error {
+	if err := setInputs(pl["Inputs"], tx); err != nil {
+		return err
+	}
+	return setOutputs(pl["Outputs"], tx)
+}
+
+func decodeNameTx(pl map[string]interface{}, tx *Transaction) error {
+	if err := setInput(pl["Input"], tx); err != nil {
+		return err
+	}
+
+	tx.Fee = uintField(pl, "Fee")
+	tx.Name = stringField(pl, "Name")
+	tx.Data = stringField(pl, "Data")
+	return nil
+}
+
+func decodeBondTx(pl map[string]interface{}, tx *Transaction) error {
+	//bonded amount becomes power of signer as validator
+	return setInput(pl["Input"], tx)
+}
+
+func decodeUnbondTx(pl map[string]interface{}, tx *Transaction) error {
+	if err := setInput(pl["Input"], tx); err != nil {
+		return err
+	}
+
+	output, err := toTxIO(pl["Output"])
+	if err != nil {
+		return err
+	}
+	tx.Outputs = []TxIO{output}
+	tx.To = output.Address
+	return nil
+}
+
+func decodePermsTx(pl map[string]interface{}, tx *Transaction) error {
+	if err := setInput(pl["Input"], tx); err != nil {
+		return err
+	}
+
+	args, _ := pl["PermArgs"].(map[string]interface{})
+	tx.To = stringField(args, "Target")
+	tx.Data = jsonField(pl, "PermArgs")
+	return nil
+}
+
+func decodeGovTx(pl map[string]interface{}, tx *Transaction) error {
+	if err := setInputs(pl["Inputs"], tx); err != nil {
+		return err
+	}
+
+	tx.Data = jsonField(pl, "AccountUpdates")
+	return nil
+}
+
+func decodeProposalTx(pl map[string]interface{}, tx *Transaction) error {
+	if err := setInput(pl["Input"], tx); err != nil {
+		return err
+	}
+
+	tx.Data = stringField(pl, "ProposalHash")
+	if tx.Data == "" {
+		tx.Data = jsonField(pl, "Proposal")
+	}
+	return nil
+}
+
+func decodeBatchTx(pl map[string]interface{}, tx *Transaction) error {
+	if err := setInputs(pl["Inputs"], tx); err != nil {
+		return err
+	}
+
+	tx.Data = jsonField(pl, "Txs")
+	return nil
+}
+
+//setInput sets single input of tx as its sender
+func setInput(v interface{}, tx *Transaction) error {
+	input, err := toTxIO(v)
+	if err != nil {
+		return err
+	}
+
+	tx.Inputs = []TxIO{input}
+	tx.From = input.Address
+	tx.Amount = input.Amount
+	tx.Sequence = input.Sequence
+	return nil
+}
+
+//setInputs sets all inputs of tx, first input is its sender and amount is sum of all inputs
+func setInputs(v interface{}, tx *Transaction) error {
+	items, ok := v.([]interface{})
+	if !ok {
+		return fmt.Errorf("error on converting inputs")
+	}
+
+	tx.Inputs = make([]TxIO, 0, len(items))
+	tx.Amount = 0
+	for i, item := range items {
+		input, err := toTxIO(item)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			tx.From = input.Address
+			tx.Sequence = input.Sequence
+		}
+		tx.Amount += input.Amount
+		tx.Inputs = append(tx.Inputs, input)
+	}
+	return nil
+}
+
+//setOutputs sets all outputs of tx, first output is its receiver
+func setOutputs(v interface{}, tx *Transaction) error {
+	items, ok := v.([]interface{})
+	if !ok {
+		return fmt.Errorf("error on converting outputs")
+	}
+
+	tx.Outputs = make([]TxIO, 0, len(items))
+	for i, item := range items {
+		output, err := toTxIO(item)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			tx.To = output.Address
+		}
+		tx.Outputs = append(tx.Outputs, output)
+	}
+	return nil
+}
+
+//toTxIO converts an input or output of payload to TxIO
+func toTxIO(v interface{}) (TxIO, error) {
+	var io TxIO
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return io, fmt.Errorf("error on converting input or output")
+	}
+
+	io.Address = stringField(m, "Address")
+	io.Amount = uintField(m, "Amount")
+	io.Sequence = uintField(m, "Sequence")
+	return io, nil
+}
+
+//uintField returns a number field of payload or zero if it is not set
+func uintField(m map[string]interface{}, key string) uint64 {
+	switch v := m[key].(type) {
+	case nil:
+		return 0
+	case float64:
+		return uint64(v)
+	default:
+		n, _ := strconv.ParseUint(fmt.Sprintf("%v", v), 10, 64)
+		return n
+	}
+}
+
+//stringField returns a string field of payload or empty string if it is not set
+func stringField(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+//jsonField returns a field of payload as json or empty string if it is not set
+func jsonField(m map[string]interface{}, key string) string {
+	if m[key] == nil {
+		return ""
+	}
+	b, err := json.Marshal(m[key])
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,74 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeTestTx(t *testing.T, txType string, payload string) *Transaction {
+	var pl map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(payload), &pl))
+
+	tx := &Transaction{Type: txType, Hash: "T"}
+	require.NoError(t, decodeTx(txType, pl, tx))
+	return tx
+}
+
+func TestDecodeCallTx(t *testing.T) {
+	tx := decodeTestTx(t, "CallTx", `{
+		"Input": {"Address": "A1", "Amount": 100, "Sequence": 4},
+		"Address": "C1", "GasLimit": 5000, "Fee": 10, "Data": "ABCD"
+	}`)
+
+	require.Equal(t, "A1", tx.From)
+	require.Equal(t, "C1", tx.To)
+	require.Equal(t, uint64(100), tx.Amount)
+	require.Equal(t, uint64(10), tx.Fee)
+	require.Equal(t, uint64(5000), tx.GasLimit)
+	require.Equal(t, uint64(4), tx.Sequence)
+	require.Equal(t, "ABCD", tx.Data)
+	require.Equal(t, []TxIO{{Address: "A1", Amount: 100, Sequence: 4}}, tx.Inputs)
+	//output is what callee receives, input pays the fee
+	require.Equal(t, []TxIO{{Address: "C1", Amount: 90}}, tx.Outputs)
+
+	//output agrees with balance change of callee
+	changes := BalanceChanges([]Transaction{*tx}, []TxExecution{{TxHash: "T", Succeeded: true}}, nil)
+	require.Equal(t, change{"C1", 90, ReasonOutput}, toChanges(changes)[2])
+}
+
+func TestDecodeCallTxFeeAboveInput(t *testing.T) {
+	tx := decodeTestTx(t, "CallTx", `{"Input": {"Address": "A1", "Amount": 5}, "Address": "C1", "Fee": 10}`)
+	require.Equal(t, []TxIO{{Address: "C1", Amount: 0}}, tx.Outputs)
+}
+
+func TestDecodeCallTxCreatesContract(t *testing.T) {
+	tx := decodeTestTx(t, "CallTx", `{"Input": {"Address": "A1", "Amount": 100}, "Fee": 10, "Data": "6060"}`)
+	require.Equal(t, "", tx.To)
+	require.Empty(t, tx.Outputs)
+	require.True(t, IsContractCreation(tx))
+}
+
+func TestDecodeSendTx(t *testing.T) {
+	tx := decodeTestTx(t, "SendTx", `{
+		"Inputs": [{"Address": "A1", "Amount": 60, "Sequence": 1}, {"Address": "A2", "Amount": 50, "Sequence": 7}],
+		"Outputs": [{"Address": "B1", "Amount": 100}]
+	}`)
+
+	require.Equal(t, "A1", tx.From)
+	require.Equal(t, uint64(110), tx.Amount)
+	require.Len(t, tx.Inputs, 2)
+	require.Equal(t, []TxIO{{Address: "B1", Amount: 100}}, tx.Outputs)
+}
+
+func TestDecodeTxErrors(t *testing.T) {
+	var pl map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"Input": "A1"}`), &pl))
+	require.Error(t, decodeTx("CallTx", pl, &Transaction{}))
+
+	//txs without a decoder only keep their type and hash
+	tx := &Transaction{Type: "UnknownTx", Hash: "T"}
+	require.NoError(t, decodeTx("UnknownTx", pl, tx))
+	require.Equal(t, &Transaction{Type: "UnknownTx", Hash: "T"}, tx)
+}
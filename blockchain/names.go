@@ -0,0 +1,56 @@
+package blockchain
+
+import (
+	"strings"
+)
+
+//MinNameRegistrationPeriod is min number of blocks that a name can be registered for
+const MinNameRegistrationPeriod = 5
+
+//nameCostPerBlock returns cost of keeping data of a name for one block
+func nameCostPerBlock(data string) uint64 {
+	return uint64(len(data)) + 32
+}
+
+//ApplyNameTx returns entry of name registry after NameTx is executed on prev entry of its name,
+//prev is nil if name is not registered. It returns false if tx fails, the same way as Burrow
+//checks it. Value that is paid after fee buys blocks of registration, an owner keeps credit
+//of remaining blocks when updating its name and an empty update by owner deletes the name
+func ApplyNameTx(tx *Transaction, prev *NameEntry) (*NameEntry, bool) {
+	name, data := tx.Name, tx.Data
+	if name == "" || tx.Amount < tx.Fee {
+		return nil, false
+	}
+
+	height := tx.BlockID
+	value := tx.Amount - tx.Fee
+	entry := &NameEntry{Name: name, Data: data, Owner: tx.From, Height: height, TxHash: tx.Hash}
+
+	if prev != nil && !prev.Deleted && prev.Expires > height {
+		if !strings.EqualFold(prev.Owner, tx.From) {
+			return nil, false
+		}
+
+		if value == 0 && data == "" {
+			entry.Data = prev.Data
+			entry.Expires = height
+			entry.Deleted = true
+			return entry, true
+		}
+
+		credit := uint64(prev.Expires-height)*nameCostPerBlock(prev.Data) + value
+		expiresIn := int64(credit / nameCostPerBlock(data))
+		if expiresIn < MinNameRegistrationPeriod {
+			return nil, false
+		}
+		entry.Expires = height + expiresIn
+		return entry, true
+	}
+
+	expiresIn := int64(value / nameCostPerBlock(data))
+	if expiresIn < MinNameRegistrationPeriod {
+		return nil, false
+	}
+	entry.Expires = height + expiresIn
+	return entry, true
+}
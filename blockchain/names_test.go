@@ -0,0 +1,71 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyNameTx(t *testing.T) {
+	//data of hello costs 37 per block, so 5 blocks cost 185
+	nameTx := func(from string, data string, value uint64) *Transaction {
+		return &Transaction{Hash: "T", BlockID: 100, From: from, Name: "alice", Data: data, Fee: 1, Amount: value + 1}
+	}
+	active := &NameEntry{Name: "alice", Data: "hello", Owner: "A1", Height: 90, Expires: 105}
+
+	tests := []struct {
+		name    string
+		tx      *Transaction
+		prev    *NameEntry
+		ok      bool
+		owner   string
+		data    string
+		expires int64
+		deleted bool
+	}{
+		{name: "new", tx: nameTx("A1", "hello", 370), ok: true, owner: "A1", data: "hello", expires: 110},
+		{name: "new for min period", tx: nameTx("A1", "hello", 185), ok: true, owner: "A1", data: "hello", expires: 105},
+		{name: "new with remainder", tx: nameTx("A1", "hello", 221), ok: true, owner: "A1", data: "hello", expires: 105},
+		{name: "new below min period", tx: nameTx("A1", "hello", 184)},
+		{name: "new with empty data", tx: nameTx("A1", "", 160), ok: true, owner: "A1", expires: 105},
+		{name: "amount below fee", tx: &Transaction{BlockID: 100, From: "A1", Name: "alice", Data: "hello", Fee: 10, Amount: 9}},
+		{name: "empty name", tx: &Transaction{BlockID: 100, From: "A1", Data: "hello", Amount: 1000}},
+		{name: "extended by owner", tx: nameTx("A1", "hello", 74), prev: active, ok: true, owner: "A1", data: "hello", expires: 107},
+		{name: "extended by owner in other case", tx: nameTx("a1", "hello", 74), prev: active, ok: true, owner: "a1", data: "hello", expires: 107},
+		{name: "updated by owner with credit", tx: nameTx("A1", "hey", 0), prev: active, ok: true, owner: "A1", data: "hey", expires: 105},
+		{name: "updated by owner below min period", tx: nameTx("A1", "hello world", 0), prev: active},
+		{name: "taken by other owner", tx: nameTx("B1", "hello", 1000), prev: active},
+		{
+			name: "expired taken by other owner", tx: nameTx("B1", "hello", 185),
+			prev: &NameEntry{Name: "alice", Data: "hello", Owner: "A1", Height: 90, Expires: 100},
+			ok:   true, owner: "B1", data: "hello", expires: 105,
+		},
+		{
+			name: "expired below min period", tx: nameTx("A1", "hello", 100),
+			prev: &NameEntry{Name: "alice", Data: "hello", Owner: "A1", Height: 90, Expires: 99},
+		},
+		{
+			name: "deleted taken by other owner", tx: nameTx("B1", "hello", 185),
+			prev: &NameEntry{Name: "alice", Data: "hello", Owner: "A1", Height: 95, Expires: 95, Deleted: true},
+			ok:   true, owner: "B1", data: "hello", expires: 105,
+		},
+		{name: "deleted by owner", tx: nameTx("A1", "", 0), prev: active, ok: true, owner: "A1", data: "hello", expires: 100, deleted: true},
+		{name: "deleted by other owner", tx: nameTx("B1", "", 0), prev: active},
+	}
+
+	for _, test := range tests {
+		entry, ok := ApplyNameTx(test.tx, test.prev)
+		require.Equal(t, test.ok, ok, test.name)
+		if !test.ok {
+			require.Nil(t, entry, test.name)
+			continue
+		}
+
+		require.Equal(t, test.tx.Name, entry.Name, test.name)
+		require.Equal(t, test.owner, entry.Owner, test.name)
+		require.Equal(t, test.data, entry.Data, test.name)
+		require.Equal(t, test.expires, entry.Expires, test.name)
+		require.Equal(t, test.deleted, entry.Deleted, test.name)
+		require.Equal(t, test.tx.BlockID, entry.Height, test.name)
+	}
+}
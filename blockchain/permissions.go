@@ -0,0 +1,216 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+//PermFlag is a bitset of Burrow permissions
+type PermFlag uint64
+
+//Permission flags of Burrow, bits are in the same order as Burrow's permission package
+//where identify comes right after batch
+const (
+	PermRoot PermFlag = 1 << iota
+	PermSend
+	PermCall
+	PermCreateContract
+	PermCreateAccount
+	PermBond
+	PermName
+	PermProposal
+	PermInput
+	PermBatch
+	PermIdentify
+	PermHasBase
+	PermSetBase
+	PermUnsetBase
+	PermSetGlobal
+	PermHasRole
+	PermAddRole
+	PermRemoveRole
+)
+
+//permNames keeps names of flags in order of their bits, the same names that Burrow uses
+var permNames = []string{
+	"root", "send", "call", "createContract", "createAccount", "bond", "name", "proposal", "input", "batch",
+	"identify", "hasBase", "setBase", "unsetBase", "setGlobal", "hasRole", "addRole", "removeRole",
+}
+
+//Actions of permission changes, govern is an update of account by GovTx
+const (
+	PermActionSetBase    = "setBase"
+	PermActionUnsetBase  = "unsetBase"
+	PermActionSetGlobal  = "setGlobal"
+	PermActionAddRole    = "addRole"
+	PermActionRemoveRole = "removeRole"
+	PermActionGovern     = "govern"
+)
+
+//Names returns names of flags that are set in bitset
+func (f PermFlag) Names() []string {
+	names := make([]string, 0)
+	for i, name := range permNames {
+		if f&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+//PermFlagFromNames returns bitset of flags with given names, unknown names are ignored
+func PermFlagFromNames(names []string) PermFlag {
+	var f PermFlag
+	for _, name := range names {
+		for i, n := range permNames {
+			if strings.EqualFold(n, name) {
+				f |= 1 << uint(i)
+			}
+		}
+	}
+	return f
+}
+
+//Permissions is a structured form of permissions of an account. Perms are values of base flags
+//that are set for account, other flags are taken from global permissions
+type Permissions struct {
+	Perms  PermFlag
+	SetBit PermFlag
+	//Granted and Denied are names of flags that are set for account to true and false
+	Granted []string
+	Denied  []string
+	Roles   []string
+}
+
+//ParsePermissions decodes permissions of an account that are saved as json of node response
+func ParsePermissions(str string) (*Permissions, error) {
+	perms := &Permissions{Granted: []string{}, Denied: []string{}, Roles: []string{}}
+	if str == "" {
+		return perms, nil
+	}
+
+	var details struct {
+		Base struct {
+			Perms  json.Number `json:"Perms"`
+			SetBit json.Number `json:"SetBit"`
+		} `json:"Base"`
+		Roles []string `json:"Roles"`
+	}
+	decoder := json.NewDecoder(strings.NewReader(str))
+	decoder.UseNumber()
+	if err := decoder.Decode(&details); err != nil {
+		return nil, err
+	}
+
+	p, _ := details.Base.Perms.Int64()
+	setBit, _ := details.Base.SetBit.Int64()
+	perms.Perms = PermFlag(p)
+	perms.SetBit = PermFlag(setBit)
+	perms.Granted = (perms.Perms & perms.SetBit).Names()
+	perms.Denied = (^perms.Perms & perms.SetBit).Names()
+	if details.Roles != nil {
+		perms.Roles = details.Roles
+	}
+	return perms, nil
+}
+
+//PermissionChanges returns changes of permissions that are made by a PermsTx or a GovTx,
+//their args are saved in data of tx
+func PermissionChanges(tx *Transaction) []PermissionChange {
+	switch tx.Type {
+	case "PermsTx":
+		return permsTxChanges(tx)
+	case "GovTx":
+		return govTxChanges(tx)
+	}
+	return nil
+}
+
+func permsTxChanges(tx *Transaction) []PermissionChange {
+	var args struct {
+		Action     json.Number  `json:"Action"`
+		Target     string       `json:"Target"`
+		Permission *json.Number `json:"Permission"`
+		Value      *bool        `json:"Value"`
+		Role       *string      `json:"Role"`
+	}
+	decoder := json.NewDecoder(strings.NewReader(tx.Data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&args); err != nil {
+		return nil
+	}
+
+	action, _ := args.Action.Int64()
+	change := PermissionChange{
+		Height:      tx.BlockID,
+		TxHash:      tx.Hash,
+		Address:     strings.ToUpper(args.Target),
+		Sender:      tx.From,
+		Permissions: []string{},
+		Value:       args.Value,
+		Roles:       []string{},
+	}
+
+	switch PermFlag(action) {
+	case PermSetBase:
+		change.Action = PermActionSetBase
+	case PermUnsetBase:
+		change.Action = PermActionUnsetBase
+	case PermSetGlobal:
+		change.Action = PermActionSetGlobal
+	case PermAddRole:
+		change.Action = PermActionAddRole
+	case PermRemoveRole:
+		change.Action = PermActionRemoveRole
+	default:
+		//hasBase and hasRole only check permissions
+		return nil
+	}
+
+	if args.Permission != nil {
+		flag, _ := args.Permission.Int64()
+		change.Permissions = PermFlag(flag).Names()
+	}
+	if args.Role != nil {
+		change.Roles = []string{*args.Role}
+	}
+	return []PermissionChange{change}
+}
+
+func govTxChanges(tx *Transaction) []PermissionChange {
+	var updates []struct {
+		Address     string   `json:"Address"`
+		Permissions []string `json:"Permissions"`
+		Roles       []string `json:"Roles"`
+	}
+	if err := json.Unmarshal([]byte(tx.Data), &updates); err != nil {
+		return nil
+	}
+
+	granted := true
+	changes := make([]PermissionChange, 0)
+	for _, update := range updates {
+		if update.Permissions == nil && update.Roles == nil {
+			continue
+		}
+
+		change := PermissionChange{
+			Height:      tx.BlockID,
+			TxHash:      tx.Hash,
+			Index:       int64(len(changes)),
+			Address:     strings.ToUpper(update.Address),
+			Sender:      tx.From,
+			Action:      PermActionGovern,
+			Permissions: PermFlagFromNames(update.Permissions).Names(),
+			Roles:       update.Roles,
+		}
+		if update.Permissions != nil {
+			change.Value = &granted
+		}
+		if change.Roles == nil {
+			change.Roles = []string{}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -0,0 +1,174 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermFlagBits(t *testing.T) {
+	//bits must match Burrow, flags are saved and sent by node as numbers
+	require.Equal(t, PermFlag(1), PermRoot)
+	require.Equal(t, PermFlag(4), PermCall)
+	require.Equal(t, PermFlag(512), PermBatch)
+	require.Equal(t, PermFlag(1024), PermIdentify)
+	require.Equal(t, PermFlag(2048), PermHasBase)
+	require.Equal(t, PermFlag(4096), PermSetBase)
+	require.Equal(t, PermFlag(8192), PermUnsetBase)
+	require.Equal(t, PermFlag(16384), PermSetGlobal)
+	require.Equal(t, PermFlag(32768), PermHasRole)
+	require.Equal(t, PermFlag(65536), PermAddRole)
+	require.Equal(t, PermFlag(131072), PermRemoveRole)
+
+	require.Len(t, permNames, 18)
+	all := PermFlag(1<<18 - 1)
+	require.Equal(t, permNames, all.Names())
+	require.Equal(t, all, PermFlagFromNames(permNames))
+}
+
+func TestPermFlagNames(t *testing.T) {
+	require.Equal(t, []string{}, PermFlag(0).Names())
+	require.Equal(t, []string{"send", "call", "identify"}, (PermSend | PermCall | PermIdentify).Names())
+	require.Equal(t, PermSend|PermName, PermFlagFromNames([]string{"Send", "name", "unknown"}))
+}
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		perms   PermFlag
+		setBit  PermFlag
+		granted []string
+		denied  []string
+		roles   []string
+		err     bool
+	}{
+		{name: "empty", granted: []string{}, denied: []string{}, roles: []string{}},
+		{
+			name: "granted and denied",
+			//send and call are set to true, bond is set to false, name is not set
+			str:     `{"Base":{"Perms":70,"SetBit":38},"Roles":["admin"]}`,
+			perms:   PermCall | PermSend | PermName,
+			setBit:  PermSend | PermCall | PermBond,
+			granted: []string{"send", "call"},
+			denied:  []string{"bond"},
+			roles:   []string{"admin"},
+		},
+		{
+			name:    "identify",
+			str:     `{"Base":{"Perms":1024,"SetBit":1024}}`,
+			perms:   PermIdentify,
+			setBit:  PermIdentify,
+			granted: []string{"identify"},
+			denied:  []string{},
+			roles:   []string{},
+		},
+		{
+			name:    "large numbers",
+			str:     `{"Base":{"Perms":262143,"SetBit":262143},"Roles":[]}`,
+			perms:   1<<18 - 1,
+			setBit:  1<<18 - 1,
+			granted: permNames,
+			denied:  []string{},
+			roles:   []string{},
+		},
+		{name: "invalid", str: `{"Base":`, err: true},
+	}
+
+	for _, test := range tests {
+		perms, err := ParsePermissions(test.str)
+		if test.err {
+			require.Error(t, err, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		require.Equal(t, test.perms, perms.Perms, test.name)
+		require.Equal(t, test.setBit, perms.SetBit, test.name)
+		require.Equal(t, test.granted, perms.Granted, test.name)
+		require.Equal(t, test.denied, perms.Denied, test.name)
+		require.Equal(t, test.roles, perms.Roles, test.name)
+	}
+}
+
+func TestPermsTxChanges(t *testing.T) {
+	yes := true
+	no := false
+
+	tests := []struct {
+		name   string
+		data   string
+		change *PermissionChange
+	}{
+		{
+			name: "set base",
+			data: `{"Action":4096,"Target":"b1","Permission":6,"Value":true}`,
+			change: &PermissionChange{Address: "B1", Action: PermActionSetBase,
+				Permissions: []string{"send", "call"}, Value: &yes, Roles: []string{}},
+		},
+		{
+			name: "set base to false",
+			data: `{"Action":4096,"Target":"B1","Permission":1024,"Value":false}`,
+			change: &PermissionChange{Address: "B1", Action: PermActionSetBase,
+				Permissions: []string{"identify"}, Value: &no, Roles: []string{}},
+		},
+		{
+			name: "unset base",
+			data: `{"Action":8192,"Target":"B1","Permission":32}`,
+			change: &PermissionChange{Address: "B1", Action: PermActionUnsetBase,
+				Permissions: []string{"bond"}, Roles: []string{}},
+		},
+		{
+			name: "set global",
+			data: `{"Action":16384,"Target":"","Permission":64,"Value":true}`,
+			change: &PermissionChange{Address: "", Action: PermActionSetGlobal,
+				Permissions: []string{"name"}, Value: &yes, Roles: []string{}},
+		},
+		{
+			name: "add role",
+			data: `{"Action":65536,"Target":"B1","Role":"admin"}`,
+			change: &PermissionChange{Address: "B1", Action: PermActionAddRole,
+				Permissions: []string{}, Roles: []string{"admin"}},
+		},
+		{
+			name: "remove role",
+			data: `{"Action":131072,"Target":"B1","Role":"admin"}`,
+			change: &PermissionChange{Address: "B1", Action: PermActionRemoveRole,
+				Permissions: []string{}, Roles: []string{"admin"}},
+		},
+		{name: "has base", data: `{"Action":2048,"Target":"B1","Permission":2}`},
+		{name: "has role", data: `{"Action":32768,"Target":"B1","Role":"admin"}`},
+		{name: "not a moderator flag", data: `{"Action":2,"Target":"B1"}`},
+		{name: "invalid", data: `{"Action":`},
+	}
+
+	for _, test := range tests {
+		tx := &Transaction{Type: "PermsTx", Hash: "T", BlockID: 9, From: "A1", Data: test.data}
+		changes := PermissionChanges(tx)
+		if test.change == nil {
+			require.Empty(t, changes, test.name)
+			continue
+		}
+
+		test.change.Height = 9
+		test.change.TxHash = "T"
+		test.change.Sender = "A1"
+		require.Equal(t, []PermissionChange{*test.change}, changes, test.name)
+	}
+}
+
+func TestGovTxChanges(t *testing.T) {
+	yes := true
+	tx := &Transaction{Type: "GovTx", Hash: "T", BlockID: 9, From: "A1", Data: `[
+		{"Address":"b1","Permissions":["send","identify"],"Roles":["admin"]},
+		{"Address":"B2","Amounts":[{"Amount":10}]},
+		{"Address":"B3","Roles":["user"]}
+	]`}
+
+	changes := PermissionChanges(tx)
+	require.Equal(t, []PermissionChange{
+		{Height: 9, TxHash: "T", Index: 0, Address: "B1", Sender: "A1", Action: PermActionGovern,
+			Permissions: []string{"send", "identify"}, Value: &yes, Roles: []string{"admin"}},
+		{Height: 9, TxHash: "T", Index: 1, Address: "B3", Sender: "A1", Action: PermActionGovern,
+			Permissions: []string{}, Roles: []string{"user"}},
+	}, changes)
+}
@@ -26,21 +26,32 @@ type GRPCConfig struct {
 }
 
 type DataBaseConfig struct {
-	Type     string `toml:"type"`
-	DBName   string `toml:"dbname"`
-	Host     string `toml:"host"`
-	Port     int    `toml:"port"`
-	User     string `toml:"user"`
-	Password string `toml:"password"`
+	Type        string `toml:"type"`
+	DBName      string `toml:"dbname"`
+	Host        string `toml:"host"`
+	Port        int    `toml:"port"`
+	User        string `toml:"user"`
+	Password    string `toml:"password"`
+	AutoMigrate bool   `toml:"auto migrate"`
 }
 
 type RestfulServerConfig struct {
-	Host string `toml:"host"`
-	Port string `toml:"port"`
+	Host       string `toml:"host"`
+	Port       string `toml:"port"`
+	AdminToken string `toml:"admin token"`
 }
 
 type AppConfig struct {
-	CheckingInterval int `toml:"checking interval"`
+	CheckingInterval int    `toml:"checking interval"`
+	MaxReorgDepth    int    `toml:"max reorg depth"`
+	FetchWorkers     int    `toml:"fetch workers"`
+	FetchQueueDepth  int    `toml:"fetch queue depth"`
+	BulkInsert       bool   `toml:"bulk insert"`
+	GapScanInterval  int    `toml:"gap scan interval"`
+	TrackSignatures  bool   `toml:"track signatures"`
+	IndexExecutions  bool   `toml:"index executions"`
+	ABIDir           string `toml:"abi dir"`
+	RichListInterval int    `toml:"rich list interval"`
 }
 
 func DefaultGRPCConfig() *GRPCConfig {
@@ -53,25 +64,36 @@ func DefaultGRPCConfig() *GRPCConfig {
 
 func DefaultDataBaseConfig() *DataBaseConfig {
 	return &DataBaseConfig{
-		Type:     "Postgre",
-		DBName:   "HubbleScan",
-		Host:     "localhost",
-		Port:     5432,
-		User:     "postgres",
-		Password: "123456",
+		Type:        "Postgre",
+		DBName:      "HubbleScan",
+		Host:        "localhost",
+		Port:        5432,
+		User:        "postgres",
+		Password:    "123456",
+		AutoMigrate: true,
 	}
 }
 
 func DefaultRestfulServerConfig() *RestfulServerConfig {
 	return &RestfulServerConfig{
-		Host: "0.0.0.0",
-		Port: "8080",
+		Host:       "0.0.0.0",
+		Port:       "8080",
+		AdminToken: "",
 	}
 }
 
 func DefaultAppConfig() *AppConfig {
 	return &AppConfig{
 		CheckingInterval: 1000,
+		MaxReorgDepth:    100,
+		FetchWorkers:     4,
+		FetchQueueDepth:  8,
+		BulkInsert:       true,
+		GapScanInterval:  0,
+		TrackSignatures:  false,
+		IndexExecutions:  true,
+		ABIDir:           "",
+		RichListInterval: 60000,
 	}
 }
 
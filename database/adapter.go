@@ -1,6 +1,8 @@
 package database
 
 import (
+	"time"
+
 	hsBC "github.com/BurrowBlocks/blockchain"
 )
 
@@ -9,6 +11,7 @@ type UserAccount struct {
 	ID      uint64
 	Address string
 	NumTxs  uint64
+	Balance uint64
 	Txs     []hsBC.Transaction
 }
 
@@ -24,43 +27,354 @@ type BlockTime struct {
 	Duration uint64
 }
 
+//SyncState defines last block height that is fully saved in database. Derived state of txs, e.g. executions,
+//names and balance changes, is only saved up to DerivedHeight, heights after it are synced without it
+type SyncState struct {
+	ChainID       string
+	LastHeight    uint64
+	DerivedHeight uint64
+}
+
+//HeightRange defines a range of block heights
+type HeightRange struct {
+	From uint64
+	To   uint64
+}
+
+//BlockFilter defines conditions for listing blocks, zero values are not checked
+type BlockFilter struct {
+	MinHeight   int64
+	MaxHeight   int64
+	FromTime    time.Time
+	ToTime      time.Time
+	Proposer    string
+	OnlyWithTxs bool
+	Ascending   bool
+	AfterHeight int64 //height of last block in previous page
+	Limit       uint64
+}
+
+//Validator defines a validator with its proposal stats
+type Validator struct {
+	Address            string
+	PublicKey          string
+	Power              uint64
+	VotingPowerPercent float64
+	Active             bool
+	FirstHeight        int64
+	LastChangeHeight   int64
+	ProposedBlocks     uint64
+	LastProposedHeight int64
+	SignedBlocks       uint64
+	MissedBlocks       uint64
+	//Uptime is percentage of signed blocks to tracked blocks. It is null when signatures of
+	//validator are not tracked
+	Uptime *float64
+}
+
+//ValidatorSetChange defines a change of validator power, zero power means validator is not in set
+type ValidatorSetChange struct {
+	Height   int64
+	Address  string
+	OldPower uint64
+	NewPower uint64
+}
+
+//ValidatorLiveness defines signed and missed blocks of a validator in a window of latest heights
+type ValidatorLiveness struct {
+	Address          string
+	SignedBlocks     uint64
+	MissedBlocks     uint64
+	LastSignedHeight int64
+}
+
+//ValidatorSignature defines whether validator signed commit of a block
+type ValidatorSignature struct {
+	Height int64
+	Signed bool
+}
+
+//Log defines an EVM log that is emitted by a contract
+type Log struct {
+	Height   int64
+	TxHash   string
+	LogIndex int64
+	Address  string
+	Topics   []string
+	Data     string
+}
+
+//LogFilter defines conditions for listing logs, a log matches a list if it matches any item
+//of the list and empty lists are not checked
+type LogFilter struct {
+	TxHash     string
+	Addresses  []string
+	Topics     [4][]string
+	FromHeight int64
+	ToHeight   int64
+	//AfterHeight and AfterIndex are position of last log in previous page
+	AfterHeight int64
+	AfterIndex  int64
+	Limit       uint64
+}
+
+//Contract defines a deployed contract with number of its transactions
+type Contract struct {
+	Address  string
+	Creator  string
+	TxID     uint64
+	TxHash   string
+	Height   int64
+	Time     string
+	CodeHash string
+	HasABI   bool
+	NumTxs   uint64
+}
+
+//ContractFilter defines conditions for listing contracts, latest contract first
+type ContractFilter struct {
+	Creator string
+	//BeforeHeight and BeforeTxID are position of last contract in previous page
+	BeforeHeight int64
+	BeforeTxID   uint64
+	Limit        uint64
+}
+
+//BalancePoint defines balance of an address after all its changes at a height
+type BalancePoint struct {
+	Height  int64
+	Time    string
+	Delta   int64
+	Balance int64
+}
+
+//BalanceHistoryFilter defines conditions for listing balance history of an address in order of height
+type BalanceHistoryFilter struct {
+	FromHeight  int64
+	ToHeight    int64
+	AfterHeight int64 //height of last point in previous page
+	Limit       uint64
+}
+
+//VotingPowerFilter defines conditions for listing voting power history in order of height
+type VotingPowerFilter struct {
+	FromHeight  int64
+	ToHeight    int64
+	AfterHeight int64 //height of last point in previous page
+	Limit       uint64
+}
+
+//RichListAccount defines rank of an account in rich list, volume is sum of sent and
+//received value and supply percent is share of balance in sum of all balances
+type RichListAccount struct {
+	Rank          uint64
+	Address       string
+	Balance       uint64
+	NumTxs        uint64
+	Sent          uint64
+	Received      uint64
+	Volume        uint64
+	SupplyPercent float64
+}
+
+//Orders of rich list
+const (
+	RichListByBalance = "balance"
+	RichListByTxs     = "txs"
+	RichListByVolume  = "volume"
+)
+
+//Name defines an entry of name registry, expired is checked against last saved block
+type Name struct {
+	Name    string
+	Data    string
+	Owner   string
+	Expires int64
+	Height  int64
+	TxHash  string
+	Deleted bool
+	Expired bool
+}
+
+//ValidatorPower defines voting power of a validator and its share of total power
+type ValidatorPower struct {
+	Address      string
+	Power        uint64
+	SharePercent float64
+}
+
+//VotingPowerPoint defines voting power of validators after all changes at a height
+type VotingPowerPoint struct {
+	Height     int64
+	TotalPower uint64
+	Validators []ValidatorPower
+}
+
 //Adapter for data base
 type Adapter interface {
 	Connect() error
 	Disconnect() error
 
+	//Begin starts a transaction, queries of returned adapter run inside it
+	Begin() (TxAdapter, error)
+
 	//Account Handling
 	InsertAccount(acc *hsBC.Account) error
 	UpdateAccount(id int, acc *hsBC.Account) error
+	//SaveAccounts inserts accounts or updates saved accounts with same address
+	SaveAccounts(accs []*hsBC.Account) error
 	GetAccount(id int) (*hsBC.Account, error)
 	GetAccountByAddress(address string) (*hsBC.Account, error)
 	GetAccountAllTransactions(address string) ([]hsBC.Transaction, error)
+	//GetAccountTransactions returns transactions minID to maxID of address history with its number of txs
 	GetAccountTransactions(address string, minID uint64, maxID uint64) ([]hsBC.Transaction, uint64, error)
 	GetAccountsTableLastID() (uint64, error)
+	//GetAccountTxsPage returns transactions of address that are after given block id and tx id
+	GetAccountTxsPage(address string, afterBlockID int64, afterID uint64, limit uint64) ([]hsBC.Transaction, error)
 
 	//Blocks Handling
 	InsertBlock(b *hsBC.BlockInfo) error
 	UpdateBlock(id int, b *hsBC.Block) error
 	UpdateBlockDuration(height int64, duration uint64) error
 	GetBlock(id int) (*hsBC.Block, error)
+	GetBlockHash(height int64) (string, error)
 	GetBlocksTableLastID() (uint64, error)
+	//GetBlocksList returns blocks that match filter
+	GetBlocksList(filter BlockFilter) ([]hsBC.Block, error)
+	//RollbackBlocks removes blocks from given height with their transactions
+	RollbackBlocks(fromHeight int64) error
+	//InsertBlocksBulk saves a batch of blocks with one round trip
+	InsertBlocksBulk(blocks []hsBC.BlockInfo) error
 
 	GetBlocksDurations(blockscount uint64) ([]BlockTime, error)
 
+	//GetMissingBlockRanges returns ranges of heights up to maxHeight that are not saved
+	GetMissingBlockRanges(maxHeight uint64) ([]HeightRange, error)
+	//GetBlocksWithMissingTxs returns heights of blocks whose txcounts does not match saved transactions
+	GetBlocksWithMissingTxs(maxHeight uint64) ([]uint64, error)
+
 	//Transactions Handling
 	InsertTx(b *hsBC.Transaction) error
 	UpdateTx(id int, b *hsBC.Transaction) error
 	GetTx(hash string) (*hsBC.Transaction, string, error)
+	//GetTxInputsOutputs returns inputs and outputs of a transaction
+	GetTxInputsOutputs(hash string) ([]hsBC.TxIO, []hsBC.TxIO, error)
+	//InsertTxExecutions saves execution results and events of saved transactions
+	InsertTxExecutions(execs []hsBC.TxExecution) error
+	//GetTxExecution returns execution result and events of a transaction or nil if it is not saved
+	GetTxExecution(hash string) (*hsBC.TxExecution, error)
+	//GetLogs returns logs that match filter in order of height and log index
+	GetLogs(filter LogFilter) ([]Log, error)
 	GetTXsTableLastID() (uint64, error)
+	//GetLatestTxsPage returns transactions that are before given block id and tx id, latest transaction first
+	GetLatestTxsPage(beforeBlockID int64, beforeID uint64, limit uint64) ([]hsBC.Transaction, error)
+	//InsertTxsBulk saves a batch of transactions and updates user accounts with one round trip
+	InsertTxsBulk(txs []hsBC.Transaction) error
 
 	GetCumulativeTxsCount(barscount uint64) ([]CumBlock, error)
 
+	//GetSyncState returns sync checkpoint or nil if nothing is synced yet
+	GetSyncState() (*SyncState, error)
+	//UpdateSyncState saves height of last fully saved block, derived height is never after it
+	UpdateSyncState(chainID string, height int64) error
+	//UpdateDerivedHeight saves height of last block whose derived state is saved
+	UpdateDerivedHeight(height int64) error
+	//RollbackDerivedState removes state that is derived from txs from given height, so it can be derived again
+	RollbackDerivedState(fromHeight int64) error
+
 	//InsertUserAccount add a unique user account in database if it not exist
 	InsertUserAccount(address string, numtxs uint64) error
 	//GetUserAccount returns a user account details
 	GetUserAccount(address string) (*UserAccount, error)
+	//GetAccountsPage returns user accounts with id greater than afterID
+	GetAccountsPage(afterID uint64, limit uint64) ([]UserAccount, error)
 	//UpdateUserAccount modifies all fields for selected user account
 	UpdateUserAccount(address string, numtxs uint64) error
 	//InsertOrAddTxToUserAccount inserts new account if not exist or add one to num_txs
 	InsertOrAddTxToUserAccount(address string) error
+
+	//Validators Handling
+	//HasGenesisValidators checks whether genesis validator set is saved
+	HasGenesisValidators() (bool, error)
+	//SaveGenesisValidators saves validators of genesis as set of first block
+	SaveGenesisValidators(validators []hsBC.Validator) error
+	//SaveValidatorKeys updates public keys of saved validators
+	SaveValidatorKeys(validators []hsBC.Validator) error
+	//ReconcileValidators saves a change at height for each validator whose power in set of node is not same as its power
+	ReconcileValidators(validators []hsBC.Validator, height int64) error
+	//GetValidators returns all validators that have been in set, active ones first
+	GetValidators() ([]Validator, error)
+	//GetValidator returns a validator details
+	GetValidator(address string) (*Validator, error)
+	//GetValidatorSetChanges returns latest changes of validator power, latest change first
+	GetValidatorSetChanges(address string, limit uint64) ([]ValidatorSetChange, error)
+	//InsertValidatorPowerChanges saves power changes of saved bond and unbond transactions, saved changes are skipped
+	InsertValidatorPowerChanges(changes []hsBC.ValidatorPowerChange) error
+	//GetValidatorPower returns latest known power of validator before height
+	GetValidatorPower(address string, beforeHeight int64) (uint64, error)
+	//GetValidatorPowerChanges returns latest power changes of validator by bond and unbond txs, latest change first
+	GetValidatorPowerChanges(address string, limit uint64) ([]hsBC.ValidatorPowerChange, error)
+	//GetVotingPowerHistory returns voting power of validators at each height in range that it is changed
+	GetVotingPowerHistory(filter VotingPowerFilter) ([]VotingPowerPoint, error)
+	//InsertCommitSignatures saves signers of last commit of blocks and absent validators of their set
+	InsertCommitSignatures(blocks []hsBC.BlockInfo) error
+	//GetValidatorsLiveness returns signed and missed blocks of validators in last window heights
+	GetValidatorsLiveness(window uint64) ([]ValidatorLiveness, error)
+	//GetValidatorSignatures returns signatures of validator in last window heights, latest first
+	GetValidatorSignatures(address string, window uint64) ([]ValidatorSignature, error)
+
+	//Contracts Handling
+	//InsertContracts saves deployed contracts of saved transactions, saved contracts are skipped
+	InsertContracts(contracts []hsBC.Contract) error
+	//GetContracts returns contracts that match filter, latest contract first
+	GetContracts(filter ContractFilter) ([]Contract, error)
+	//GetContract returns a contract details
+	GetContract(address string) (*Contract, error)
+
+	//Balances Handling
+	//InsertBalanceChanges saves balance changes of saved transactions, saved changes are skipped
+	InsertBalanceChanges(changes []hsBC.BalanceChange) error
+	//HasGenesisBalances checks whether opening balances of genesis accounts are saved
+	HasGenesisBalances() (bool, error)
+	//SaveGenesisBalances saves balances of genesis accounts as changes at height 0
+	SaveGenesisBalances(accs []hsBC.Account) error
+	//ReconcileBalances saves a change for each account whose balance in ledger is not same as its balance
+	ReconcileBalances(accs []*hsBC.Account, height int64) error
+	//GetBalanceHistory returns balance of address at each height that it is changed
+	GetBalanceHistory(address string, filter BalanceHistoryFilter) ([]BalancePoint, error)
+
+	//RefreshRichList recomputes ranks of accounts in rich list
+	RefreshRichList() error
+	//GetRichList returns accounts in order of their rank by balance, txs or volume after given rank
+	GetRichList(order string, afterRank uint64, limit uint64) ([]RichListAccount, error)
+
+	//Names Handling
+	//InsertNames saves entries of name registry that are set by saved transactions
+	InsertNames(entries []hsBC.NameEntry) error
+	//GetName returns latest entry of a name or nil if it is never registered
+	GetName(name string) (*Name, error)
+	//GetNameHistory returns latest entries of a name, latest entry first
+	GetNameHistory(name string, limit uint64) ([]Name, error)
+	//GetAccountNames returns names whose latest entry is owned by address
+	GetAccountNames(address string) ([]Name, error)
+
+	//Permissions Handling
+	//InsertPermissionChanges saves permission changes of saved transactions, saved changes are skipped
+	InsertPermissionChanges(changes []hsBC.PermissionChange) error
+	//GetPermissionChanges returns latest permission changes of address, latest change first
+	GetPermissionChanges(address string, limit uint64) ([]hsBC.PermissionChange, error)
+
+	//ABIs Handling
+	//SaveABI saves ABI json of a contract and replaces its previous ABI
+	SaveABI(address string, abi string) error
+	//GetABI returns ABI json of a contract or empty string if it has no ABI
+	GetABI(address string) (string, error)
+}
+
+//TxAdapter is a data base adapter bound to a transaction
+type TxAdapter interface {
+	Adapter
+
+	Commit() error
+	Rollback() error
 }
@@ -0,0 +1,536 @@
+package database
+
+//migration is a versioned change of database schema
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+//migrations are applied in order of version, new migrations should be added at the end
+var migrations = []migration{
+	{
+		//legacy databases may have duplicate rows that were saved before keys existed, so they are
+		//removed first. Latest row of a block, first tx of a hash and the row with most txs of an
+		//address are kept, accounts that still share an id get new ids after the largest one
+		version: 1,
+		name:    "initial schema",
+		up: `
+		CREATE TABLE IF NOT EXISTS accounts (
+			id serial NOT NULL,
+			address character varying(256),
+			balance bigint,
+			permission character varying(256),
+			sequence bigint,
+			code character varying,
+			CONSTRAINT accounts_pkey PRIMARY KEY (id)
+		);
+
+		CREATE TABLE IF NOT EXISTS blocks (
+			height bigint NOT NULL,
+			hash character varying(256),
+			chainid text,
+			"time" timestamp without time zone,
+			txcounts bigint,
+			duration bigint DEFAULT 0
+		);
+
+		CREATE TABLE IF NOT EXISTS transactions (
+			id serial NOT NULL,
+			block_id integer,
+			txhash character varying(256),
+			fee bigint,
+			gas_limit bigint,
+			data character varying,
+			addr_from character varying(64),
+			addr_to character varying(64),
+			amount bigint,
+			tx_type character varying(10),
+			CONSTRAINT transactions_pkey PRIMARY KEY (id)
+		);
+
+		CREATE TABLE IF NOT EXISTS useraccounts (
+			id bigserial NOT NULL,
+			address character varying(256) NOT NULL,
+			num_txs bigint DEFAULT 0 NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS sync_state (
+			id smallint DEFAULT 1 NOT NULL,
+			chainid text NOT NULL,
+			last_height bigint DEFAULT 0 NOT NULL,
+			derived_height bigint DEFAULT 0 NOT NULL,
+			updated_at timestamp without time zone DEFAULT now() NOT NULL,
+			CONSTRAINT sync_state_pkey PRIMARY KEY (id)
+		);
+
+		DELETE FROM blocks a USING blocks b
+		WHERE a.height = b.height AND a.ctid < b.ctid;
+
+		DELETE FROM transactions a USING transactions b
+		WHERE a.txhash = b.txhash AND a.id > b.id;
+
+		DELETE FROM useraccounts a USING useraccounts b
+		WHERE a.address = b.address AND (a.num_txs < b.num_txs OR (a.num_txs = b.num_txs AND a.ctid < b.ctid));
+
+		WITH dups AS (
+			SELECT ctid, row_number() OVER (ORDER BY id, ctid) as n FROM (
+				SELECT ctid, id, row_number() OVER (PARTITION BY id ORDER BY ctid) as k FROM useraccounts
+			) ids
+			WHERE k > 1
+		), top AS (
+			SELECT MAX(id) as id FROM useraccounts
+		)
+		UPDATE useraccounts u SET id = top.id + dups.n
+		FROM dups, top
+		WHERE u.ctid = dups.ctid;
+
+		SELECT setval(pg_get_serial_sequence('useraccounts', 'id'), MAX(id)) FROM useraccounts WHERE id > 0;
+
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'blocks_pkey') THEN
+				ALTER TABLE blocks ADD CONSTRAINT blocks_pkey PRIMARY KEY (height);
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'transactions_txhash_key') THEN
+				ALTER TABLE transactions ADD CONSTRAINT transactions_txhash_key UNIQUE (txhash);
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'useraccounts_pkey') THEN
+				ALTER TABLE useraccounts ADD CONSTRAINT useraccounts_pkey PRIMARY KEY (id);
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'useraccounts_address_key') THEN
+				ALTER TABLE useraccounts ADD CONSTRAINT useraccounts_address_key UNIQUE (address);
+			END IF;
+		END $$;
+		`,
+		down: `
+		DROP TABLE IF EXISTS sync_state;
+		DROP TABLE IF EXISTS useraccounts;
+		DROP TABLE IF EXISTS transactions;
+		DROP TABLE IF EXISTS blocks;
+		DROP TABLE IF EXISTS accounts;
+		`,
+	},
+	{
+		//databases created by old script/HubbleScan.sql dump saved numbers of accounts as text and float
+		version: 2,
+		name:    "accounts column types",
+		up: `
+		ALTER TABLE accounts
+			ALTER COLUMN balance TYPE bigint USING balance::bigint,
+			ALTER COLUMN sequence TYPE bigint USING NULLIF(sequence::text, '')::bigint,
+			ALTER COLUMN code TYPE character varying;
+		`,
+		down: `
+		ALTER TABLE accounts
+			ALTER COLUMN balance TYPE double precision USING balance::double precision,
+			ALTER COLUMN sequence TYPE character varying(256) USING sequence::text,
+			ALTER COLUMN code TYPE character varying(256);
+		`,
+	},
+	{
+		//txhash is already indexed by transactions_txhash_key
+		version: 3,
+		name:    "transactions address indexes",
+		up: `
+		CREATE INDEX IF NOT EXISTS transactions_addr_from_idx ON transactions (addr_from, block_id, id);
+		CREATE INDEX IF NOT EXISTS transactions_addr_to_idx ON transactions (addr_to, block_id, id);
+		CREATE INDEX IF NOT EXISTS transactions_block_id_idx ON transactions (block_id, id);
+		`,
+		down: `
+		DROP INDEX IF EXISTS transactions_block_id_idx;
+		DROP INDEX IF EXISTS transactions_addr_to_idx;
+		DROP INDEX IF EXISTS transactions_addr_from_idx;
+		`,
+	},
+	{
+		version: 4,
+		name:    "blocks proposer and filter indexes",
+		up: `
+		ALTER TABLE blocks ADD COLUMN IF NOT EXISTS proposer_address character varying(64);
+		CREATE INDEX IF NOT EXISTS blocks_proposer_address_idx ON blocks (proposer_address, height);
+		CREATE INDEX IF NOT EXISTS blocks_time_idx ON blocks ("time");
+		CREATE INDEX IF NOT EXISTS blocks_with_txs_idx ON blocks (height) WHERE txcounts > 0;
+		`,
+		down: `
+		DROP INDEX IF EXISTS blocks_with_txs_idx;
+		DROP INDEX IF EXISTS blocks_time_idx;
+		DROP INDEX IF EXISTS blocks_proposer_address_idx;
+		ALTER TABLE blocks DROP COLUMN IF EXISTS proposer_address;
+		`,
+	},
+	{
+		version: 5,
+		name:    "blocks header columns",
+		up: `
+		ALTER TABLE blocks
+			ADD COLUMN IF NOT EXISTS total_txs bigint,
+			ADD COLUMN IF NOT EXISTS last_block_hash character varying(256),
+			ADD COLUMN IF NOT EXISTS last_commit_hash character varying(256),
+			ADD COLUMN IF NOT EXISTS data_hash character varying(256),
+			ADD COLUMN IF NOT EXISTS validators_hash character varying(256),
+			ADD COLUMN IF NOT EXISTS next_validators_hash character varying(256),
+			ADD COLUMN IF NOT EXISTS consensus_hash character varying(256),
+			ADD COLUMN IF NOT EXISTS app_hash character varying(256),
+			ADD COLUMN IF NOT EXISTS last_results_hash character varying(256),
+			ADD COLUMN IF NOT EXISTS evidence_hash character varying(256);
+		`,
+		down: `
+		ALTER TABLE blocks
+			DROP COLUMN IF EXISTS evidence_hash,
+			DROP COLUMN IF EXISTS last_results_hash,
+			DROP COLUMN IF EXISTS app_hash,
+			DROP COLUMN IF EXISTS consensus_hash,
+			DROP COLUMN IF EXISTS next_validators_hash,
+			DROP COLUMN IF EXISTS validators_hash,
+			DROP COLUMN IF EXISTS data_hash,
+			DROP COLUMN IF EXISTS last_commit_hash,
+			DROP COLUMN IF EXISTS last_block_hash,
+			DROP COLUMN IF EXISTS total_txs;
+		`,
+	},
+	{
+		//power of validators that are not in set anymore is zero, reconciled changes set power of
+		//validators to their power in set of node when it is not same as derived power
+		version: 6,
+		name:    "validators",
+		up: `
+		CREATE TABLE IF NOT EXISTS validators (
+			address character varying(64) NOT NULL,
+			pub_key character varying(256),
+			power bigint DEFAULT 0 NOT NULL,
+			first_height bigint NOT NULL,
+			last_change_height bigint NOT NULL,
+			CONSTRAINT validators_pkey PRIMARY KEY (address)
+		);
+
+		CREATE TABLE IF NOT EXISTS validator_set_changes (
+			id bigserial NOT NULL,
+			height bigint NOT NULL,
+			address character varying(64) NOT NULL,
+			old_power bigint NOT NULL,
+			new_power bigint NOT NULL,
+			reconciled boolean DEFAULT false NOT NULL,
+			CONSTRAINT validator_set_changes_pkey PRIMARY KEY (id)
+		);
+
+		CREATE INDEX IF NOT EXISTS validator_set_changes_address_idx ON validator_set_changes (address, height);
+		CREATE INDEX IF NOT EXISTS validator_set_changes_height_idx ON validator_set_changes (height);
+		`,
+		down: `
+		DROP TABLE IF EXISTS validator_set_changes;
+		DROP TABLE IF EXISTS validators;
+		`,
+	},
+	{
+		//signatures of a height are read from last commit of next block
+		version: 7,
+		name:    "validator signatures",
+		up: `
+		CREATE TABLE IF NOT EXISTS validator_signatures (
+			height bigint NOT NULL,
+			address character varying(64) NOT NULL,
+			signed boolean NOT NULL,
+			CONSTRAINT validator_signatures_pkey PRIMARY KEY (address, height)
+		);
+
+		CREATE INDEX IF NOT EXISTS validator_signatures_height_idx ON validator_signatures (height);
+		`,
+		down: `
+		DROP TABLE IF EXISTS validator_signatures;
+		`,
+	},
+	{
+		//accounts are refreshed from node by address, so only the latest row of an address is kept
+		version: 8,
+		name:    "accounts address key",
+		up: `
+		DELETE FROM accounts a USING accounts b
+		WHERE a.address = b.address AND a.id < b.id;
+
+		ALTER TABLE accounts ADD COLUMN IF NOT EXISTS updated_at timestamp without time zone DEFAULT now() NOT NULL;
+		ALTER TABLE accounts ALTER COLUMN permission TYPE character varying;
+
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'accounts_address_key') THEN
+				ALTER TABLE accounts ADD CONSTRAINT accounts_address_key UNIQUE (address);
+			END IF;
+		END $$;
+		`,
+		down: `
+		ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_address_key;
+		ALTER TABLE accounts ALTER COLUMN permission TYPE character varying(256) USING left(permission, 256);
+		ALTER TABLE accounts DROP COLUMN IF EXISTS updated_at;
+		`,
+	},
+	{
+		version: 9,
+		name:    "transaction inputs and outputs",
+		up: `
+		ALTER TABLE transactions ALTER COLUMN tx_type TYPE character varying(32);
+
+		CREATE TABLE IF NOT EXISTS tx_inputs (
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			idx integer NOT NULL,
+			address character varying(64) NOT NULL,
+			amount bigint DEFAULT 0 NOT NULL,
+			sequence bigint DEFAULT 0 NOT NULL,
+			CONSTRAINT tx_inputs_pkey PRIMARY KEY (tx_id, idx)
+		);
+
+		CREATE TABLE IF NOT EXISTS tx_outputs (
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			idx integer NOT NULL,
+			address character varying(64) NOT NULL,
+			amount bigint DEFAULT 0 NOT NULL,
+			CONSTRAINT tx_outputs_pkey PRIMARY KEY (tx_id, idx)
+		);
+
+		CREATE INDEX IF NOT EXISTS tx_inputs_address_idx ON tx_inputs (address, tx_id);
+		CREATE INDEX IF NOT EXISTS tx_outputs_address_idx ON tx_outputs (address, tx_id);
+		`,
+		down: `
+		DROP TABLE IF EXISTS tx_outputs;
+		DROP TABLE IF EXISTS tx_inputs;
+		ALTER TABLE transactions ALTER COLUMN tx_type TYPE character varying(10) USING left(tx_type, 10);
+		`,
+	},
+	{
+		version: 10,
+		name:    "transaction executions and events",
+		up: `
+		CREATE TABLE IF NOT EXISTS tx_executions (
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			succeeded boolean NOT NULL,
+			gas_used bigint DEFAULT 0 NOT NULL,
+			return_value character varying,
+			exception_code integer DEFAULT 0 NOT NULL,
+			exception character varying,
+			creates_contract boolean DEFAULT false NOT NULL,
+			contract_address character varying(64),
+			CONSTRAINT tx_executions_pkey PRIMARY KEY (tx_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS tx_events (
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			idx integer NOT NULL,
+			event_type character varying(32) NOT NULL,
+			event_id character varying(256),
+			data character varying,
+			CONSTRAINT tx_events_pkey PRIMARY KEY (tx_id, idx)
+		);
+
+		CREATE INDEX IF NOT EXISTS tx_executions_failed_idx ON tx_executions (tx_id) WHERE NOT succeeded;
+		`,
+		down: `
+		DROP TABLE IF EXISTS tx_events;
+		DROP TABLE IF EXISTS tx_executions;
+		`,
+	},
+	{
+		//log_index is position of log in its block
+		version: 11,
+		name:    "evm logs",
+		up: `
+		CREATE TABLE IF NOT EXISTS logs (
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			height bigint NOT NULL,
+			txhash character varying(256) NOT NULL,
+			log_index integer NOT NULL,
+			address character varying(64) NOT NULL,
+			topic0 character varying(64),
+			topic1 character varying(64),
+			topic2 character varying(64),
+			topic3 character varying(64),
+			data character varying,
+			CONSTRAINT logs_pkey PRIMARY KEY (height, log_index)
+		);
+
+		CREATE INDEX IF NOT EXISTS logs_address_idx ON logs (address, height, log_index);
+		CREATE INDEX IF NOT EXISTS logs_topic0_idx ON logs (topic0, height, log_index);
+		CREATE INDEX IF NOT EXISTS logs_topic1_idx ON logs (topic1, height, log_index);
+		CREATE INDEX IF NOT EXISTS logs_topic2_idx ON logs (topic2, height, log_index);
+		CREATE INDEX IF NOT EXISTS logs_topic3_idx ON logs (topic3, height, log_index);
+		CREATE INDEX IF NOT EXISTS logs_tx_id_idx ON logs (tx_id);
+		`,
+		down: `
+		DROP TABLE IF EXISTS logs;
+		`,
+	},
+	{
+		//abi is json of contract ABI that is uploaded or loaded from abi dir
+		version: 12,
+		name:    "contract abis",
+		up: `
+		CREATE TABLE IF NOT EXISTS abis (
+			address character varying(64) NOT NULL,
+			abi text NOT NULL,
+			updated_at timestamp without time zone DEFAULT now() NOT NULL,
+			CONSTRAINT abis_pkey PRIMARY KEY (address)
+		);
+		`,
+		down: `
+		DROP TABLE IF EXISTS abis;
+		`,
+	},
+	{
+		//code_hash is keccak256 of deployed code, it is empty when code could not be read
+		version: 13,
+		name:    "contracts",
+		up: `
+		CREATE TABLE IF NOT EXISTS contracts (
+			address character varying(64) NOT NULL,
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			txhash character varying(256) NOT NULL,
+			creator character varying(64) NOT NULL,
+			height bigint NOT NULL,
+			code_hash character varying(64),
+			CONSTRAINT contracts_pkey PRIMARY KEY (address)
+		);
+
+		CREATE INDEX IF NOT EXISTS contracts_height_idx ON contracts (height, tx_id);
+		CREATE INDEX IF NOT EXISTS contracts_creator_idx ON contracts (creator, height, tx_id);
+		`,
+		down: `
+		DROP TABLE IF EXISTS contracts;
+		`,
+	},
+	{
+		//reconcile changes have no tx, they fix the ledger to balance that node reports
+		version: 14,
+		name:    "balance changes",
+		up: `
+		CREATE TABLE IF NOT EXISTS balance_changes (
+			id bigserial NOT NULL,
+			tx_id integer REFERENCES transactions (id) ON DELETE CASCADE,
+			txhash character varying(256),
+			height bigint NOT NULL,
+			idx integer NOT NULL,
+			address character varying(64) NOT NULL,
+			delta bigint NOT NULL,
+			reason character varying(16) NOT NULL,
+			CONSTRAINT balance_changes_pkey PRIMARY KEY (id)
+		);
+
+		CREATE UNIQUE INDEX IF NOT EXISTS balance_changes_tx_idx ON balance_changes (tx_id, idx);
+		CREATE INDEX IF NOT EXISTS balance_changes_address_idx ON balance_changes (address, height, id);
+		CREATE INDEX IF NOT EXISTS balance_changes_height_idx ON balance_changes (height);
+		`,
+		down: `
+		DROP TABLE IF EXISTS balance_changes;
+		`,
+	},
+	{
+		//rich_list ranks accounts that have txs or are reported by node, it is refreshed by sync
+		version: 15,
+		name:    "rich list",
+		up: `
+		CREATE MATERIALIZED VIEW IF NOT EXISTS rich_list AS
+		WITH holders AS (
+			SELECT coalesce(u.address, a.address) as address, coalesce(u.num_txs, 0) as num_txs,
+				coalesce(a.balance, 0) as balance
+			FROM useraccounts u
+			FULL JOIN accounts a ON a.address = u.address
+		), volumes AS (
+			SELECT address,
+				coalesce(SUM(-delta) FILTER (WHERE delta<0 AND reason IN ('input', 'call')), 0) as sent,
+				coalesce(SUM(delta) FILTER (WHERE delta>0 AND reason IN ('output', 'call')), 0) as received
+			FROM balance_changes
+			GROUP BY address
+		)
+		SELECT h.address, h.balance, h.num_txs,
+			coalesce(v.sent, 0)::bigint as sent, coalesce(v.received, 0)::bigint as received,
+			row_number() OVER (ORDER BY h.balance DESC, h.address) as balance_rank,
+			row_number() OVER (ORDER BY h.num_txs DESC, h.address) as txs_rank,
+			row_number() OVER (ORDER BY coalesce(v.sent, 0) + coalesce(v.received, 0) DESC, h.address) as volume_rank
+		FROM holders h
+		LEFT JOIN volumes v ON v.address = h.address;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS rich_list_address_idx ON rich_list (address);
+		CREATE INDEX IF NOT EXISTS rich_list_balance_rank_idx ON rich_list (balance_rank);
+		CREATE INDEX IF NOT EXISTS rich_list_txs_rank_idx ON rich_list (txs_rank);
+		CREATE INDEX IF NOT EXISTS rich_list_volume_rank_idx ON rich_list (volume_rank);
+		`,
+		down: `
+		DROP MATERIALIZED VIEW IF EXISTS rich_list;
+		`,
+	},
+	{
+		//each NameTx that changes registry adds an entry, latest entry of a name is its state
+		version: 16,
+		name:    "names",
+		up: `
+		CREATE TABLE IF NOT EXISTS names (
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			txhash character varying(256) NOT NULL,
+			height bigint NOT NULL,
+			name character varying(256) NOT NULL,
+			data character varying,
+			owner character varying(64) NOT NULL,
+			expires bigint NOT NULL,
+			deleted boolean DEFAULT false NOT NULL,
+			CONSTRAINT names_pkey PRIMARY KEY (tx_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS names_name_idx ON names (name, height DESC, tx_id DESC);
+		CREATE INDEX IF NOT EXISTS names_owner_idx ON names (owner);
+		`,
+		down: `
+		DROP TABLE IF EXISTS names;
+		`,
+	},
+	{
+		//permissions is bitset of flags that action is done on, value is null for roles
+		version: 17,
+		name:    "permission changes",
+		up: `
+		CREATE TABLE IF NOT EXISTS permission_changes (
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			idx integer NOT NULL,
+			txhash character varying(256) NOT NULL,
+			height bigint NOT NULL,
+			address character varying(64) NOT NULL,
+			sender character varying(64) NOT NULL,
+			action character varying(16) NOT NULL,
+			permissions bigint DEFAULT 0 NOT NULL,
+			value boolean,
+			roles text[] DEFAULT '{}' NOT NULL,
+			CONSTRAINT permission_changes_pkey PRIMARY KEY (tx_id, idx)
+		);
+
+		CREATE INDEX IF NOT EXISTS permission_changes_address_idx ON permission_changes (address, height DESC);
+		`,
+		down: `
+		DROP TABLE IF EXISTS permission_changes;
+		`,
+	},
+	{
+		//power is voting power of validator after the change
+		version: 18,
+		name:    "validator power changes",
+		up: `
+		CREATE TABLE IF NOT EXISTS validator_power_changes (
+			tx_id integer NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+			txhash character varying(256) NOT NULL,
+			height bigint NOT NULL,
+			address character varying(64) NOT NULL,
+			delta bigint NOT NULL,
+			power bigint NOT NULL,
+			CONSTRAINT validator_power_changes_pkey PRIMARY KEY (tx_id)
+		);
+
+		CREATE INDEX IF NOT EXISTS validator_power_changes_address_idx ON validator_power_changes (address, height DESC, tx_id DESC);
+		CREATE INDEX IF NOT EXISTS validator_power_changes_height_idx ON validator_power_changes (height);
+		`,
+		down: `
+		DROP TABLE IF EXISTS validator_power_changes;
+		`,
+	},
+}
+
+//LatestSchemaVersion returns version of last migration
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationsAreOrdered(t *testing.T) {
+	require.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		require.Equal(t, i+1, m.version, m.name)
+		require.NotEmpty(t, m.name)
+		require.NotEmpty(t, strings.TrimSpace(m.up), m.name)
+		require.NotEmpty(t, strings.TrimSpace(m.down), m.name)
+	}
+	require.Equal(t, len(migrations), LatestSchemaVersion())
+}
+
+func TestMigrationsRemoveDuplicatesBeforeKeys(t *testing.T) {
+	//a unique key can not be added while duplicates exist, so each one must be preceded by a delete
+	keys := map[string]string{
+		"blocks_pkey":              "DELETE FROM blocks",
+		"transactions_txhash_key":  "DELETE FROM transactions",
+		"useraccounts_address_key": "DELETE FROM useraccounts",
+		"useraccounts_pkey":        "UPDATE useraccounts u SET id",
+		"accounts_address_key":     "DELETE FROM accounts",
+	}
+
+	for key, dedupe := range keys {
+		found := false
+		for _, m := range migrations {
+			add := strings.Index(m.up, "ADD CONSTRAINT "+key)
+			if add < 0 {
+				continue
+			}
+			found = true
+			remove := strings.Index(m.up, dedupe)
+			require.True(t, remove >= 0 && remove < add, key)
+		}
+		require.True(t, found, key)
+	}
+}
@@ -3,16 +3,33 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"math"
 
 	hsBC "github.com/BurrowBlocks/blockchain"
 	config "github.com/BurrowBlocks/config"
 	_ "github.com/lib/pq" //dependency for postgre
 )
 
+//querier runs queries on database or inside a transaction
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 //Postgre adapter
 type Postgre struct {
 	Config *config.Config
 	ObjDB  *sql.DB //Opened DB
+	objTx  *sql.Tx //Running transaction, nil if adapter is not transactional
+}
+
+//conn returns running transaction or opened DB
+func (obe *Postgre) conn() querier {
+	if obe.objTx != nil {
+		return obe.objTx
+	}
+	return obe.ObjDB
 }
 
 //Connect to database
@@ -33,6 +50,14 @@ func (obe *Postgre) Connect() error {
 		obe.ObjDB.Close()
 		return err
 	}
+
+	if obe.Config.DataBase.AutoMigrate {
+		err = obe.Migrate()
+		if err != nil {
+			obe.ObjDB.Close()
+			return err
+		}
+	}
 	return nil
 }
 
@@ -42,6 +67,56 @@ func (obe *Postgre) Disconnect() error {
 	return closeError
 }
 
+//Begin starts a database transaction and returns an adapter that runs all queries inside it
+func (obe *Postgre) Begin() (TxAdapter, error) {
+	if obe.objTx != nil {
+		return nil, fmt.Errorf("transaction is already started")
+	}
+
+	dbTx, err := obe.ObjDB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Postgre{Config: obe.Config, ObjDB: obe.ObjDB, objTx: dbTx}, nil
+}
+
+//Commit commits running transaction
+func (obe *Postgre) Commit() error {
+	if obe.objTx == nil {
+		return fmt.Errorf("no transaction is started")
+	}
+	return obe.objTx.Commit()
+}
+
+//Rollback aborts running transaction
+func (obe *Postgre) Rollback() error {
+	if obe.objTx == nil {
+		return fmt.Errorf("no transaction is started")
+	}
+	return obe.objTx.Rollback()
+}
+
+//runInTx runs fn inside running transaction or inside a new one if adapter is not transactional
+func (obe *Postgre) runInTx(fn func(txAdapter *Postgre) error) error {
+	if obe.objTx != nil {
+		return fn(obe)
+	}
+
+	txAdapter, err := obe.Begin()
+	if err != nil {
+		return err
+	}
+
+	pgTx := txAdapter.(*Postgre)
+	if err := fn(pgTx); err != nil {
+		pgTx.Rollback()
+		return err
+	}
+
+	return pgTx.Commit()
+}
+
 //InsertAccount add new Account to accounts table
 func (obe *Postgre) InsertAccount(acc *hsBC.Account) error {
 
@@ -49,7 +124,7 @@ func (obe *Postgre) InsertAccount(acc *hsBC.Account) error {
 				VALUES ($1, $2, $3, $4, $5)
 				RETURNING id`
 	id := 0
-	err := obe.ObjDB.QueryRow(sqlStatement, acc.Address, acc.Balance, acc.Permission, acc.Sequence, acc.Code).Scan(&id)
+	err := obe.conn().QueryRow(sqlStatement, acc.Address, acc.Balance, acc.Permission, acc.Sequence, acc.Code).Scan(&id)
 	if err != nil {
 		return err
 	}
@@ -64,7 +139,7 @@ func (obe *Postgre) UpdateAccount(id int, acc *hsBC.Account) error {
 				RETURNING id, address;`
 	var retAddress string
 	var retID int
-	err := obe.ObjDB.QueryRow(sqlStatement, id, acc.Address, acc.Balance, acc.Permission, acc.Sequence, acc.Code).Scan(&retID, &retAddress)
+	err := obe.conn().QueryRow(sqlStatement, id, acc.Address, acc.Balance, acc.Permission, acc.Sequence, acc.Code).Scan(&retID, &retAddress)
 
 	if err != nil {
 		return err
@@ -73,13 +148,33 @@ func (obe *Postgre) UpdateAccount(id int, acc *hsBC.Account) error {
 	return nil
 }
 
+//SaveAccounts inserts accounts or updates saved accounts with same address
+func (obe *Postgre) SaveAccounts(accs []*hsBC.Account) error {
+
+	sqlStatement := `INSERT INTO accounts (address, balance, permission, sequence, code, updated_at)
+				VALUES ($1, $2, $3, $4, $5, now())
+				ON CONFLICT (address) DO UPDATE
+				SET balance = EXCLUDED.balance, permission = EXCLUDED.permission, sequence = EXCLUDED.sequence,
+					code = EXCLUDED.code, updated_at = EXCLUDED.updated_at;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		for _, acc := range accs {
+			_, err := txAdapter.conn().Exec(sqlStatement, acc.Address, acc.Balance, acc.Permission, acc.Sequence, acc.Code)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 //GetAccount finds account in db and returns its data
 func (obe *Postgre) GetAccount(id int) (*hsBC.Account, error) {
-	sqlStatement := `SELECT * FROM accounts 
+	sqlStatement := `SELECT id, address, balance, permission, sequence, coalesce(code, '') FROM accounts
 					 WHERE id=$1;`
 	acc := &hsBC.Account{Address: "", Balance: 0.0, Permission: "", Sequence: 0, Code: ""}
-	row := obe.ObjDB.QueryRow(sqlStatement, id)
-	err := row.Scan(&acc.Address, &acc.ID, &acc.Balance, &acc.Permission, &acc.Sequence, &acc.Code)
+	row := obe.conn().QueryRow(sqlStatement, id)
+	err := row.Scan(&acc.ID, &acc.Address, &acc.Balance, &acc.Permission, &acc.Sequence, &acc.Code)
 	switch err {
 	case sql.ErrNoRows:
 		return nil, err
@@ -92,11 +187,11 @@ func (obe *Postgre) GetAccount(id int) (*hsBC.Account, error) {
 
 //GetAccountByAddress finds account in db and returns its data
 func (obe *Postgre) GetAccountByAddress(address string) (*hsBC.Account, error) {
-	sqlStatement := `SELECT * FROM accounts 
+	sqlStatement := `SELECT id, address, balance, permission, sequence, coalesce(code, '') FROM accounts
 					 WHERE address=$1;`
 	acc := &hsBC.Account{Address: "", Balance: 0.0, Permission: "", Sequence: 0, Code: ""}
-	row := obe.ObjDB.QueryRow(sqlStatement, address)
-	err := row.Scan(&acc.Address, &acc.ID, &acc.Balance, &acc.Permission, &acc.Sequence, &acc.Code)
+	row := obe.conn().QueryRow(sqlStatement, address)
+	err := row.Scan(&acc.ID, &acc.Address, &acc.Balance, &acc.Permission, &acc.Sequence, &acc.Code)
 	switch err {
 	case sql.ErrNoRows:
 		return nil, err
@@ -109,15 +204,24 @@ func (obe *Postgre) GetAccountByAddress(address string) (*hsBC.Account, error) {
 
 //GetAccountFromTransactions finds accounts in db.transactions using address and returns its data
 func (obe *Postgre) GetAccountAllTransactions(address string) ([]hsBC.Transaction, error) {
-	sqlStatement := `SELECT block_id,txhash,fee,gas_limit,data,addr_from,addr_to,amount,tx_type FROM transactions 
-					 WHERE addr_from=$1 OR addr_to=$1;`
-
-	rows, errGetTxs := obe.ObjDB.Query(sqlStatement, address)
-	defer rows.Close()
-
+	sqlStatement := `SELECT block_id,txhash,fee,gas_limit,data,addr_from,addr_to,amount,tx_type FROM transactions
+					 WHERE id IN
+					 (
+						SELECT id FROM transactions WHERE addr_from=$1
+						UNION
+						SELECT id FROM transactions WHERE addr_to=$1
+						UNION
+						SELECT tx_id FROM tx_inputs WHERE address=$1
+						UNION
+						SELECT tx_id FROM tx_outputs WHERE address=$1
+					 )
+					 ORDER BY block_id, id;`
+
+	rows, errGetTxs := obe.conn().Query(sqlStatement, address)
 	if errGetTxs != nil {
 		return nil, errGetTxs
 	}
+	defer rows.Close()
 
 	txs := make([]hsBC.Transaction, 0)
 	for rows.Next() {
@@ -137,65 +241,44 @@ func (obe *Postgre) GetAccountAllTransactions(address string) ([]hsBC.Transactio
 	return txs, nil
 }
 
-//GetAccountTransactions finds transactions using address and min and max ID
+//GetAccountTransactions returns transactions minID to maxID of address history in order of block
+//and number of all transactions of address that is kept in its user account. Rows are numbered
+//from 1, they are read with the keyset page of history from its start, so whole range is returned
 func (obe *Postgre) GetAccountTransactions(address string, minID uint64, maxID uint64) ([]hsBC.Transaction, uint64, error) {
 
-	sqlStatement1 := `SELECT COUNT(*) FROM 
-					(
-					SELECT ROW_NUMBER () OVER (ORDER BY block_id) as txid,block_id,txhash,fee,gas_limit,data,addr_from,addr_to,amount,tx_type FROM transactions 
-										WHERE addr_from=$1 OR addr_to=$1
-					)
-					x;`
-
-	row := obe.ObjDB.QueryRow(sqlStatement1, address)
-	count := uint64(0)
-	err := row.Scan(&count)
+	sqlCount := `SELECT coalesce((SELECT num_txs FROM useraccounts WHERE address=$1), 0);`
 
-	if err == sql.ErrNoRows {
-		return nil, 0, fmt.Errorf("not found")
-	} else if err != nil {
+	var count uint64
+	if err := obe.conn().QueryRow(sqlCount, address).Scan(&count); err != nil {
 		return nil, 0, err
 	}
 
-	sqlStatement2 := `SELECT * FROM 
-					(
-					SELECT ROW_NUMBER () OVER (ORDER BY block_id) as txid,block_id,txhash,fee,gas_limit,data,addr_from,addr_to,amount,tx_type FROM transactions 
-										 WHERE addr_from=$1 OR addr_to=$1
-					)
-					x WHERE txid>=$2 AND txid<=$3;`
-
-	rows, errGetTxs := obe.ObjDB.Query(sqlStatement2, address, minID, maxID)
-	defer rows.Close()
-
-	if errGetTxs != nil {
-		return nil, 0, errGetTxs
+	if minID < 1 {
+		minID = 1
 	}
-
-	txs := make([]hsBC.Transaction, 0)
-	for rows.Next() {
-
-		var txn hsBC.Transaction
-		var id uint64
-
-		if err := rows.Scan(&id, &txn.BlockID, &txn.Hash, &txn.Fee, &txn.GasLimit, &txn.Data, &txn.From, &txn.To, &txn.Amount, &txn.Type); err != nil {
-			return nil, 0, err
-		}
-
-		txs = append(txs, txn)
+	if maxID < minID {
+		return make([]hsBC.Transaction, 0), count, nil
+	}
+	//ids of txs are integer, so a history never has more rows
+	if maxID > math.MaxInt32 {
+		maxID = math.MaxInt32
 	}
 
-	if err := rows.Err(); err != nil {
+	txs, err := obe.GetAccountTxsPage(address, 0, 0, maxID)
+	if err != nil {
 		return nil, 0, err
 	}
-
-	return txs, count, nil
+	if uint64(len(txs)) < minID {
+		return make([]hsBC.Transaction, 0), count, nil
+	}
+	return txs[minID-1:], count, nil
 }
 
 //GetAccountsTableLastID returns last block number
 func (obe *Postgre) GetAccountsTableLastID() (uint64, error) {
 	sqlStatement := `SELECT coalesce(MAX(id), 0) as max FROM accounts;`
 
-	row := obe.ObjDB.QueryRow(sqlStatement)
+	row := obe.conn().QueryRow(sqlStatement)
 	var LastID uint64
 	err := row.Scan(&LastID)
 	switch err {
@@ -208,13 +291,19 @@ func (obe *Postgre) GetAccountsTableLastID() (uint64, error) {
 	}
 }
 
-//InsertBlock add a block in database
+//InsertBlock add a block in database or updates it if already saved
 func (obe *Postgre) InsertBlock(b *hsBC.BlockInfo) error {
-	sqlStatement := `INSERT INTO blocks (height, hash, chainID, time, txcounts, duration)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	sqlStatement := `INSERT INTO blocks (height, hash, chainID, time, txcounts, duration, proposer_address,
+		total_txs, last_block_hash, last_commit_hash, data_hash, validators_hash, next_validators_hash,
+		consensus_hash, app_hash, last_results_hash, evidence_hash)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	ON CONFLICT (height) DO UPDATE
+	SET ` + blocksUpsertSet + `
 	RETURNING height`
 	id := 0
-	row := obe.ObjDB.QueryRow(sqlStatement, b.Height, b.BlockHash, b.ChainID, b.Time, b.NumTxs, 0)
+	row := obe.conn().QueryRow(sqlStatement, b.Height, b.BlockHash, b.ChainID, b.Time, b.NumTxs, 0, b.ProposerAddress,
+		b.TotalTxs, b.LastBlockHash, b.LastCommitHash, b.DataHash, b.ValidatorsHash, b.NextValidatorsHash,
+		b.ConsensusHash, b.AppHash, b.LastResultsHash, b.EvidenceHash)
 	err := row.Scan(&id)
 	if err != nil {
 		return err
@@ -235,7 +324,7 @@ func (obe *Postgre) UpdateBlockDuration(height int64, duration uint64) error {
 	WHERE height = $1
 	RETURNING height;`
 	var retID int
-	err := obe.ObjDB.QueryRow(sqlStatement, height, duration).Scan(&retID)
+	err := obe.conn().QueryRow(sqlStatement, height, duration).Scan(&retID)
 
 	if err != nil {
 		return err
@@ -246,24 +335,100 @@ func (obe *Postgre) UpdateBlockDuration(height int64, duration uint64) error {
 
 //GetBlock returns a block details
 func (obe *Postgre) GetBlock(id int) (*hsBC.Block, error) {
-	sqlStatement := `SELECT height, hash, chainID, time, txcounts, duration FROM blocks 
+	sqlStatement := `SELECT ` + blockColumns + ` FROM blocks
 					 WHERE height=$1;`
 
-	var b hsBC.Block
-	row := obe.ObjDB.QueryRow(sqlStatement, id)
-	err := row.Scan(&b.Height, &b.Hash, &b.ChainID, &b.Time, &b.TxCounts, &b.Duration)
-	if err != nil {
-		return nil, err
+	row := obe.conn().QueryRow(sqlStatement, id)
+	return scanBlock(row)
+}
+
+//GetBlockHash returns hash of saved block or empty string if block is not saved
+func (obe *Postgre) GetBlockHash(height int64) (string, error) {
+	sqlStatement := `SELECT hash FROM blocks
+					 WHERE height=$1;`
+
+	var hash string
+	err := obe.conn().QueryRow(sqlStatement, height).Scan(&hash)
+	switch err {
+	case sql.ErrNoRows:
+		return "", nil
+	case nil:
+		return hash, nil
+	default:
+		return "", err
 	}
+}
+
+//RollbackBlocks removes blocks from given height and their transactions and
+//decreases num_txs of user accounts touched by removed transactions
+func (obe *Postgre) RollbackBlocks(fromHeight int64) error {
+	sqlDecreaseNumTxs := `UPDATE useraccounts
+	SET num_txs = useraccounts.num_txs - removed.count
+	FROM
+	(
+		SELECT address, COUNT(*) as count FROM
+		(
+			SELECT id as tx_id, addr_from as address FROM transactions WHERE block_id>=$1 AND addr_from<>''
+			UNION
+			SELECT id, addr_to FROM transactions WHERE block_id>=$1 AND addr_to<>''
+			UNION
+			SELECT i.tx_id, i.address FROM tx_inputs i JOIN transactions t ON t.id = i.tx_id WHERE t.block_id>=$1
+			UNION
+			SELECT o.tx_id, o.address FROM tx_outputs o JOIN transactions t ON t.id = o.tx_id WHERE t.block_id>=$1
+		) tblAddresses
+		GROUP BY address
+	) removed
+	WHERE useraccounts.address = removed.address;`
+
+	sqlDeleteUserAccounts := `DELETE FROM useraccounts WHERE num_txs<=0;`
+	sqlDeleteTxs := `DELETE FROM transactions WHERE block_id>=$1;`
+	sqlDeleteBlocks := `DELETE FROM blocks WHERE height>=$1;`
+	//signatures of previous height are read from commit of first removed block
+	sqlDeleteSignatures := `DELETE FROM validator_signatures WHERE height>=$1-1;`
+	//changes of removed txs are deleted with them but reconcile changes have no tx
+	sqlDeleteBalanceChanges := `DELETE FROM balance_changes WHERE height>=$1;`
+	sqlDeleteValidatorSetChanges := `DELETE FROM validator_set_changes WHERE height>=$1;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		if _, err := conn.Exec(sqlDecreaseNumTxs, fromHeight); err != nil {
+			return err
+		}
+
+		if _, err := conn.Exec(sqlDeleteUserAccounts); err != nil {
+			return err
+		}
+
+		if _, err := conn.Exec(sqlDeleteTxs, fromHeight); err != nil {
+			return err
+		}
+
+		if _, err := conn.Exec(sqlDeleteSignatures, fromHeight); err != nil {
+			return err
+		}
+
+		if _, err := conn.Exec(sqlDeleteBalanceChanges, fromHeight); err != nil {
+			return err
+		}
 
-	return &b, nil
+		if _, err := conn.Exec(sqlDeleteValidatorSetChanges, fromHeight); err != nil {
+			return err
+		}
+
+		if _, err := conn.Exec(sqlDeleteBlocks, fromHeight); err != nil {
+			return err
+		}
+
+		//power changes of removed txs are deleted with them
+		return txAdapter.refreshValidators()
+	})
 }
 
 //GetBlocksTableLastID returns last block number
 func (obe *Postgre) GetBlocksTableLastID() (uint64, error) {
 	sqlStatement := `SELECT coalesce(MAX(height), 0) as max FROM blocks`
 
-	row := obe.ObjDB.QueryRow(sqlStatement)
+	row := obe.conn().QueryRow(sqlStatement)
 
 	var LastID uint64
 	err := row.Scan(&LastID)
@@ -281,7 +446,7 @@ func (obe *Postgre) GetBlocksTableLastID() (uint64, error) {
 func (obe *Postgre) GetBlocksCount() (uint64, error) {
 	sqlStatement := `SELECT COUNT(*) as count FROM blocks`
 
-	row := obe.ObjDB.QueryRow(sqlStatement)
+	row := obe.conn().QueryRow(sqlStatement)
 	var TxsCount uint64
 	err := row.Scan(&TxsCount)
 	switch err {
@@ -307,7 +472,7 @@ func (obe *Postgre) GetBlocksDurations(blockscount uint64) ([]BlockTime, error)
 	ORDER BY height ASC
 	;`
 
-	rows, errGetDurations := obe.ObjDB.Query(sqlStatement, blockscount)
+	rows, errGetDurations := obe.conn().Query(sqlStatement, blockscount)
 	defer rows.Close()
 
 	if errGetDurations != nil {
@@ -332,35 +497,179 @@ func (obe *Postgre) GetBlocksDurations(blockscount uint64) ([]BlockTime, error)
 	return durations, nil
 }
 
-//InsertTx add a transaction in database
+//GetMissingBlockRanges returns ranges of heights up to maxHeight that are not saved
+func (obe *Postgre) GetMissingBlockRanges(maxHeight uint64) ([]HeightRange, error) {
+
+	sqlStatement := `SELECT prev + 1 as from_height, height - 1 as to_height FROM
+	(
+		SELECT height, LAG(height, 1, 0::bigint) OVER (ORDER BY height) as prev FROM
+		(
+			SELECT height FROM blocks WHERE height>0 AND height<=$1
+			UNION ALL
+			SELECT $1::bigint + 1
+		) tblHeights
+	) tblGaps
+	WHERE height - prev > 1
+	ORDER BY from_height
+	;`
+
+	rows, errGetGaps := obe.conn().Query(sqlStatement, maxHeight)
+	if errGetGaps != nil {
+		return nil, errGetGaps
+	}
+	defer rows.Close()
+
+	gaps := make([]HeightRange, 0)
+	for rows.Next() {
+
+		var r HeightRange
+		if err := rows.Scan(&r.From, &r.To); err != nil {
+			return nil, err
+		}
+
+		gaps = append(gaps, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return gaps, nil
+}
+
+//GetBlocksWithMissingTxs returns heights of blocks whose txcounts does not match saved transactions
+func (obe *Postgre) GetBlocksWithMissingTxs(maxHeight uint64) ([]uint64, error) {
+
+	sqlStatement := `SELECT blocks.height FROM blocks
+	LEFT JOIN
+	(
+		SELECT block_id, COUNT(*) as count FROM transactions
+		GROUP BY block_id
+	) tblTxs
+	ON tblTxs.block_id = blocks.height
+	WHERE blocks.height<=$1 AND blocks.txcounts <> coalesce(tblTxs.count, 0)
+	ORDER BY blocks.height
+	;`
+
+	rows, errGetHeights := obe.conn().Query(sqlStatement, maxHeight)
+	if errGetHeights != nil {
+		return nil, errGetHeights
+	}
+	defer rows.Close()
+
+	heights := make([]uint64, 0)
+	for rows.Next() {
+
+		var height uint64
+		if err := rows.Scan(&height); err != nil {
+			return nil, err
+		}
+
+		heights = append(heights, height)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return heights, nil
+}
+
+//InsertTx add a transaction in database, it skips transactions that are already saved
 func (obe *Postgre) InsertTx(b *hsBC.Transaction) error {
 	sqlStatement := `INSERT INTO transactions (block_id, txhash, fee, gas_limit, data, addr_from, addr_to, amount, tx_type)
 	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	ON CONFLICT (txhash) DO NOTHING
 	RETURNING id`
-	id := 0
-	row := obe.ObjDB.QueryRow(sqlStatement, b.BlockID, b.Hash, b.Fee, b.GasLimit, b.Data, b.From, b.To, b.Amount, b.Type)
-	err := row.Scan(&id)
-	if err != nil {
-		return err
-	}
 
-	if b.From != "" {
-		err = obe.InsertOrAddTxToUserAccount(b.From)
-		if err != nil {
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		id := 0
+		row := txAdapter.conn().QueryRow(sqlStatement, b.BlockID, b.Hash, b.Fee, b.GasLimit, b.Data, b.From, b.To, b.Amount, b.Type)
+		err := row.Scan(&id)
+		if err == sql.ErrNoRows {
+			//already saved, user accounts are counted before
+			return nil
+		} else if err != nil {
 			return err
 		}
-	}
 
-	if b.To != "" {
-		err = obe.InsertOrAddTxToUserAccount(b.To)
-		if err != nil {
+		for _, address := range b.Addresses() {
+			err = txAdapter.InsertOrAddTxToUserAccount(address)
+			if err != nil {
+				return err
+			}
+		}
+
+		return txAdapter.insertTxIO(id, b)
+	})
+}
+
+//insertTxIO saves inputs and outputs of a saved transaction
+func (obe *Postgre) insertTxIO(txID int, b *hsBC.Transaction) error {
+	sqlInput := `INSERT INTO tx_inputs (tx_id, idx, address, amount, sequence)
+	VALUES ($1, $2, $3, $4, $5);`
+	sqlOutput := `INSERT INTO tx_outputs (tx_id, idx, address, amount)
+	VALUES ($1, $2, $3, $4);`
+
+	for i, input := range b.Inputs {
+		if _, err := obe.conn().Exec(sqlInput, txID, i, input.Address, input.Amount, input.Sequence); err != nil {
+			return err
+		}
+	}
+	for i, output := range b.Outputs {
+		if _, err := obe.conn().Exec(sqlOutput, txID, i, output.Address, output.Amount); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
+//GetTxInputsOutputs returns inputs and outputs of a transaction
+func (obe *Postgre) GetTxInputsOutputs(hash string) ([]hsBC.TxIO, []hsBC.TxIO, error) {
+	sqlInputs := `SELECT i.address, i.amount, i.sequence FROM tx_inputs i
+	JOIN transactions t ON t.id = i.tx_id
+	WHERE t.txhash=$1
+	ORDER BY i.idx;`
+	sqlOutputs := `SELECT o.address, o.amount, 0 FROM tx_outputs o
+	JOIN transactions t ON t.id = o.tx_id
+	WHERE t.txhash=$1
+	ORDER BY o.idx;`
+
+	inputs, err := obe.queryTxIO(sqlInputs, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	outputs, err := obe.queryTxIO(sqlOutputs, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inputs, outputs, nil
+}
+
+//queryTxIO reads inputs or outputs that are selected by sqlStatement
+func (obe *Postgre) queryTxIO(sqlStatement string, args ...interface{}) ([]hsBC.TxIO, error) {
+	rows, err := obe.conn().Query(sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ios := make([]hsBC.TxIO, 0)
+	for rows.Next() {
+		var io hsBC.TxIO
+		if err := rows.Scan(&io.Address, &io.Amount, &io.Sequence); err != nil {
+			return nil, err
+		}
+		ios = append(ios, io)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ios, nil
+}
+
 //UpdateTx modifies a transaction data in database
 func (obe *Postgre) UpdateTx(id int, b *hsBC.Transaction) error {
 	sqlStatement := `UPDATE transactions
@@ -369,7 +678,7 @@ func (obe *Postgre) UpdateTx(id int, b *hsBC.Transaction) error {
 	RETURNING id, txhash;`
 	var retHash string
 	var retID int
-	err := obe.ObjDB.QueryRow(sqlStatement, id, b.BlockID, b.Hash, b.Fee, b.GasLimit, b.Data, b.From, b.To, b.Amount, b.Type).Scan(&retID, &retHash)
+	err := obe.conn().QueryRow(sqlStatement, id, b.BlockID, b.Hash, b.Fee, b.GasLimit, b.Data, b.From, b.To, b.Amount, b.Type).Scan(&retID, &retHash)
 
 	if err != nil {
 		return err
@@ -384,7 +693,7 @@ func (obe *Postgre) GetTx(hash string) (*hsBC.Transaction, string, error) {
 					 WHERE txhash=$1;`
 	var tx hsBC.Transaction
 	var txtime string
-	errGetTx := obe.ObjDB.QueryRow(sqlStatement, hash).Scan(&tx.BlockID, &txtime, &tx.Hash, &tx.Fee, &tx.GasLimit, &tx.Data, &tx.From, &tx.To, &tx.Amount, &tx.Type)
+	errGetTx := obe.conn().QueryRow(sqlStatement, hash).Scan(&tx.BlockID, &txtime, &tx.Hash, &tx.Fee, &tx.GasLimit, &tx.Data, &tx.From, &tx.To, &tx.Amount, &tx.Type)
 
 	if errGetTx != nil {
 		return nil, "", errGetTx
@@ -397,7 +706,7 @@ func (obe *Postgre) GetTx(hash string) (*hsBC.Transaction, string, error) {
 func (obe *Postgre) GetTXsTableLastID() (uint64, error) {
 	sqlStatement := `SELECT coalesce(MAX(id), 0) as max FROM transactions`
 
-	row := obe.ObjDB.QueryRow(sqlStatement)
+	row := obe.conn().QueryRow(sqlStatement)
 	var LastID uint64
 	err := row.Scan(&LastID)
 	switch err {
@@ -431,7 +740,7 @@ func (obe *Postgre) GetCumulativeTxsCount(barscount uint64) ([]CumBlock, error)
 	ORDER BY tblResult.height ASC
 	`
 
-	rows, errGetTxsCount := obe.ObjDB.Query(sqlStatement, barscount)
+	rows, errGetTxsCount := obe.conn().Query(sqlStatement, barscount)
 	defer rows.Close()
 
 	if errGetTxsCount != nil {
@@ -456,11 +765,85 @@ func (obe *Postgre) GetCumulativeTxsCount(barscount uint64) ([]CumBlock, error)
 	return txscount, nil
 }
 
+//GetSyncState returns sync checkpoint or nil if nothing is synced yet
+func (obe *Postgre) GetSyncState() (*SyncState, error) {
+	sqlStatement := `SELECT chainid, last_height, derived_height FROM sync_state
+					 WHERE id=1;`
+
+	var state SyncState
+	err := obe.conn().QueryRow(sqlStatement).Scan(&state.ChainID, &state.LastHeight, &state.DerivedHeight)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, nil
+	case nil:
+		return &state, nil
+	default:
+		return nil, err
+	}
+}
+
+//UpdateSyncState saves height of last fully saved block, derived height goes back with it after a rollback
+func (obe *Postgre) UpdateSyncState(chainID string, height int64) error {
+	sqlStatement := `INSERT INTO sync_state (id, chainid, last_height, updated_at)
+	VALUES (1, $1, $2, now())
+	ON CONFLICT (id) DO UPDATE
+	SET chainid = EXCLUDED.chainid, last_height = EXCLUDED.last_height, updated_at = EXCLUDED.updated_at,
+		derived_height = LEAST(sync_state.derived_height, EXCLUDED.last_height);`
+
+	_, err := obe.conn().Exec(sqlStatement, chainID, height)
+	return err
+}
+
+//UpdateDerivedHeight saves height of last block whose derived state is saved
+func (obe *Postgre) UpdateDerivedHeight(height int64) error {
+	sqlStatement := `UPDATE sync_state SET derived_height = $1, updated_at = now()
+					 WHERE id=1;`
+
+	_, err := obe.conn().Exec(sqlStatement, height)
+	return err
+}
+
+//RollbackDerivedState removes executions, logs, contracts, names, permission changes, validator power and
+//balance changes of txs from given height with reconciled changes after them. Genesis balances and
+//validators are kept, derived height goes back to previous height
+func (obe *Postgre) RollbackDerivedState(fromHeight int64) error {
+	sqlTxIDs := `SELECT id FROM transactions WHERE block_id>=$1`
+	sqlDeletes := []string{
+		`DELETE FROM tx_events WHERE tx_id IN (` + sqlTxIDs + `);`,
+		`DELETE FROM tx_executions WHERE tx_id IN (` + sqlTxIDs + `);`,
+		`DELETE FROM logs WHERE height>=$1;`,
+		`DELETE FROM contracts WHERE height>=$1;`,
+		`DELETE FROM names WHERE height>=$1;`,
+		`DELETE FROM permission_changes WHERE height>=$1;`,
+		`DELETE FROM validator_power_changes WHERE height>=$1;`,
+		`DELETE FROM validator_set_changes WHERE height>=$1 AND reconciled;`,
+		//genesis balances are at height 0
+		`DELETE FROM balance_changes WHERE height>=$1 AND height>0;`,
+	}
+	sqlDerivedHeight := `UPDATE sync_state SET derived_height = LEAST(derived_height, $1-1)
+	WHERE id=1;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		for _, sqlStatement := range sqlDeletes {
+			if _, err := conn.Exec(sqlStatement, fromHeight); err != nil {
+				return err
+			}
+		}
+
+		if _, err := conn.Exec(sqlDerivedHeight, fromHeight); err != nil {
+			return err
+		}
+
+		return txAdapter.refreshValidators()
+	})
+}
+
 //GetTxsCount returns num transaction saved in db
 func (obe *Postgre) GetTxsCount() (uint64, error) {
 	sqlStatement := `SELECT COUNT(*) as count FROM transactions`
 
-	row := obe.ObjDB.QueryRow(sqlStatement)
+	row := obe.conn().QueryRow(sqlStatement)
 	var TxsCount uint64
 	err := row.Scan(&TxsCount)
 	switch err {
@@ -477,14 +860,13 @@ func (obe *Postgre) GetTxsCount() (uint64, error) {
 func (obe *Postgre) GetLatestTxs(count uint64) ([]hsBC.Transaction, error) {
 
 	sqlStatement := `SELECT block_id,txhash,fee,gas_limit,data,addr_from,addr_to,amount,tx_type FROM transactions
-	ORDER BY block_id DESC LIMIT $1;`
-
-	rows, errGetLatestTxs := obe.ObjDB.Query(sqlStatement, count)
-	defer rows.Close()
+	ORDER BY block_id DESC, id DESC LIMIT $1;`
 
+	rows, errGetLatestTxs := obe.conn().Query(sqlStatement, count)
 	if errGetLatestTxs != nil {
 		return nil, errGetLatestTxs
 	}
+	defer rows.Close()
 
 	txs := make([]hsBC.Transaction, 0)
 	for rows.Next() {
@@ -509,7 +891,7 @@ func (obe *Postgre) GetAccountsCount() (uint64, error) {
 
 	sqlStatement := `SELECT COUNT(*) as count FROM useraccounts`
 
-	row := obe.ObjDB.QueryRow(sqlStatement)
+	row := obe.conn().QueryRow(sqlStatement)
 	var UserAccsCount uint64
 	err := row.Scan(&UserAccsCount)
 	switch err {
@@ -525,10 +907,11 @@ func (obe *Postgre) GetAccountsCount() (uint64, error) {
 //GetAccounts returns all account of users
 func (obe *Postgre) GetAccounts(fromID uint64, toID uint64) ([]UserAccount, error) {
 
-	sqlStatement := `SELECT * FROM useraccounts 
-	WHERE id>=$1 AND id<=$2;`
+	sqlStatement := `SELECT u.id, u.address, u.num_txs, coalesce(a.balance, 0) FROM useraccounts u
+	LEFT JOIN accounts a ON a.address = u.address
+	WHERE u.id>=$1 AND u.id<=$2;`
 
-	rows, errGetUserAccs := obe.ObjDB.Query(sqlStatement, fromID, toID)
+	rows, errGetUserAccs := obe.conn().Query(sqlStatement, fromID, toID)
 	//err := row.Scan(&acc.Address, &acc.ID, &acc.Address, &acc.NumTxs)
 
 	defer rows.Close()
@@ -541,7 +924,7 @@ func (obe *Postgre) GetAccounts(fromID uint64, toID uint64) ([]UserAccount, erro
 	for rows.Next() {
 
 		var acc UserAccount
-		if err := rows.Scan(&acc.ID, &acc.Address, &acc.NumTxs); err != nil {
+		if err := rows.Scan(&acc.ID, &acc.Address, &acc.NumTxs, &acc.Balance); err != nil {
 			return nil, err
 		}
 
@@ -563,7 +946,7 @@ func (obe *Postgre) InsertUserAccount(address string, numtxs uint64) error {
 	RETURNING id`
 
 	id := 0
-	row := obe.ObjDB.QueryRow(sqlStatement, address, numtxs)
+	row := obe.conn().QueryRow(sqlStatement, address, numtxs)
 	err := row.Scan(&id)
 	if err != nil {
 		return err
@@ -573,10 +956,11 @@ func (obe *Postgre) InsertUserAccount(address string, numtxs uint64) error {
 
 //GetUserAccount returns a user account details
 func (obe *Postgre) GetUserAccount(address string) (*UserAccount, error) {
-	sqlStatement := `SELECT id, address, num_txs FROM useraccounts
-					 WHERE address=$1;`
+	sqlStatement := `SELECT u.id, u.address, u.num_txs, coalesce(a.balance, 0) FROM useraccounts u
+					 LEFT JOIN accounts a ON a.address = u.address
+					 WHERE u.address=$1;`
 	var acc UserAccount
-	errGetAcc := obe.ObjDB.QueryRow(sqlStatement, address).Scan(&acc.ID, &acc.Address, &acc.NumTxs)
+	errGetAcc := obe.conn().QueryRow(sqlStatement, address).Scan(&acc.ID, &acc.Address, &acc.NumTxs, &acc.Balance)
 
 	if errGetAcc != nil {
 		return nil, errGetAcc
@@ -592,7 +976,7 @@ func (obe *Postgre) UpdateUserAccount(address string, numtxs uint64) error {
 				WHERE address = $1
 				RETURNING id;`
 	var retID int
-	err := obe.ObjDB.QueryRow(sqlStatement, address, numtxs).Scan(&retID)
+	err := obe.conn().QueryRow(sqlStatement, address, numtxs).Scan(&retID)
 
 	if err != nil {
 		return err
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"database/sql"
+)
+
+//SaveABI saves ABI json of a contract and replaces its previous ABI
+func (obe *Postgre) SaveABI(address string, abi string) error {
+	sqlStatement := `INSERT INTO abis (address, abi)
+	VALUES ($1, $2)
+	ON CONFLICT (address) DO UPDATE
+	SET abi = EXCLUDED.abi, updated_at = now();`
+
+	_, err := obe.conn().Exec(sqlStatement, address, abi)
+	return err
+}
+
+//GetABI returns ABI json of a contract or empty string if it has no ABI
+func (obe *Postgre) GetABI(address string) (string, error) {
+	sqlStatement := `SELECT abi FROM abis WHERE address=$1;`
+
+	var abi string
+	err := obe.conn().QueryRow(sqlStatement, address).Scan(&abi)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return abi, err
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+)
+
+//InsertBalanceChanges saves balance changes of saved transactions, saved changes are skipped
+func (obe *Postgre) InsertBalanceChanges(changes []hsBC.BalanceChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	sqlStatement := `INSERT INTO balance_changes (tx_id, txhash, height, idx, address, delta, reason)
+	SELECT id, txhash, $2, $3, $4, $5, $6 FROM transactions WHERE txhash=$1
+	ON CONFLICT (tx_id, idx) DO NOTHING;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		for _, c := range changes {
+			_, err := conn.Exec(sqlStatement, c.TxHash, c.Height, c.Index, c.Address, c.Delta, c.Reason)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//HasGenesisBalances checks whether opening balances of genesis accounts are saved
+func (obe *Postgre) HasGenesisBalances() (bool, error) {
+	sqlStatement := `SELECT EXISTS (SELECT 1 FROM balance_changes WHERE reason=$1);`
+
+	var saved bool
+	err := obe.conn().QueryRow(sqlStatement, hsBC.ReasonGenesis).Scan(&saved)
+	return saved, err
+}
+
+//SaveGenesisBalances saves balances of genesis accounts as changes at height 0, so balance of
+//an address is right from its first change. Reconcile changes that were saved before made up for
+//missing genesis balances, they are removed and saved again by next reconcile if still needed
+func (obe *Postgre) SaveGenesisBalances(accs []hsBC.Account) error {
+
+	sqlSaved := `SELECT EXISTS (SELECT 1 FROM balance_changes WHERE reason=$1);`
+
+	sqlDeleteReconciled := `DELETE FROM balance_changes WHERE reason=$1;`
+
+	sqlInsert := `INSERT INTO balance_changes (height, idx, address, delta, reason)
+	VALUES (0, $1, $2, $3, $4);`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+
+		var saved bool
+		if err := conn.QueryRow(sqlSaved, hsBC.ReasonGenesis).Scan(&saved); err != nil {
+			return err
+		}
+		if saved {
+			return nil
+		}
+
+		if _, err := conn.Exec(sqlDeleteReconciled, hsBC.ReasonReconcile); err != nil {
+			return err
+		}
+		for i, acc := range accs {
+			if acc.Balance == 0 {
+				continue
+			}
+			_, err := conn.Exec(sqlInsert, i, acc.Address, int64(acc.Balance), hsBC.ReasonGenesis)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//ReconcileBalances saves a change at height for each account whose balance in ledger is not
+//same as its balance, so changes that are not seen in txs are still in history
+func (obe *Postgre) ReconcileBalances(accs []*hsBC.Account, height int64) error {
+	if len(accs) == 0 {
+		return nil
+	}
+
+	sqlStatement := `INSERT INTO balance_changes (height, idx, address, delta, reason)
+	SELECT $1, 0, $2, $3 - coalesce(SUM(delta), 0), $4 FROM balance_changes WHERE address=$2
+	HAVING $3 - coalesce(SUM(delta), 0) <> 0;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		for _, acc := range accs {
+			_, err := conn.Exec(sqlStatement, height, acc.Address, int64(acc.Balance), hsBC.ReasonReconcile)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//GetBalanceHistory returns balance of address at each height that it is changed in order of height
+func (obe *Postgre) GetBalanceHistory(address string, filter BalanceHistoryFilter) ([]BalancePoint, error) {
+
+	conditions := make([]string, 0)
+	args := []interface{}{address}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.FromHeight > 0 {
+		addCondition("h.height>=$%d", filter.FromHeight)
+	}
+	if filter.ToHeight > 0 {
+		addCondition("h.height<=$%d", filter.ToHeight)
+	}
+	addCondition("h.height>$%d", filter.AfterHeight)
+
+	args = append(args, filter.Limit)
+	//balance is summed over all changes before filter is applied
+	sqlStatement := fmt.Sprintf(`SELECT h.height, b.time, h.delta, h.balance FROM
+	(
+		SELECT height, SUM(delta) as delta, SUM(SUM(delta)) OVER (ORDER BY height) as balance
+		FROM balance_changes
+		WHERE address=$1
+		GROUP BY height
+	) h
+	LEFT JOIN blocks b ON b.height = h.height
+	WHERE %s
+	ORDER BY h.height
+	LIMIT $%d;`, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := obe.conn().Query(sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]BalancePoint, 0)
+	for rows.Next() {
+		var p BalancePoint
+		var t sql.NullString
+		if err := rows.Scan(&p.Height, &t, &p.Delta, &p.Balance); err != nil {
+			return nil, err
+		}
+		p.Time = t.String
+		points = append(points, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
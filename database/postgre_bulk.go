@@ -0,0 +1,241 @@
+package database
+
+import (
+	"database/sql"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/lib/pq"
+)
+
+//blockCopyColumns are columns of blocks staging table in order of values of blockCopyRow
+var blockCopyColumns = []string{"height", "hash", "chainid", "time", "txcounts", "proposer_address",
+	"total_txs", "last_block_hash", "last_commit_hash", "data_hash", "validators_hash", "next_validators_hash",
+	"consensus_hash", "app_hash", "last_results_hash", "evidence_hash"}
+
+//txCopyColumns are columns of transactions staging table in order of values of txCopyRow
+var txCopyColumns = []string{"seq", "block_id", "txhash", "fee", "gas_limit", "data", "addr_from", "addr_to", "amount", "tx_type"}
+
+//txIOCopyColumns are columns of inputs and outputs staging table in order of values of txIOCopyRows
+var txIOCopyColumns = []string{"txhash", "is_input", "idx", "address", "amount", "sequence"}
+
+//blockCopyRow returns values of a block that are copied into staging table
+func blockCopyRow(b *hsBC.BlockInfo) []interface{} {
+	return []interface{}{b.Height, b.BlockHash, b.ChainID, b.Time, b.NumTxs, b.ProposerAddress,
+		b.TotalTxs, b.LastBlockHash, b.LastCommitHash, b.DataHash, b.ValidatorsHash, b.NextValidatorsHash,
+		b.ConsensusHash, b.AppHash, b.LastResultsHash, b.EvidenceHash}
+}
+
+//txCopyRow returns values of a transaction that are copied into staging table, seq keeps
+//order of transactions in batch
+func txCopyRow(seq int, b *hsBC.Transaction) []interface{} {
+	return []interface{}{seq, b.BlockID, b.Hash, b.Fee, b.GasLimit, b.Data, b.From, b.To, b.Amount, b.Type}
+}
+
+//txIOCopyRows returns values of inputs and then outputs of a transaction that are copied into staging table
+func txIOCopyRows(b *hsBC.Transaction) [][]interface{} {
+	rows := make([][]interface{}, 0, len(b.Inputs)+len(b.Outputs))
+	for i, input := range b.Inputs {
+		rows = append(rows, []interface{}{b.Hash, true, i, input.Address, input.Amount, input.Sequence})
+	}
+	for i, output := range b.Outputs {
+		rows = append(rows, []interface{}{b.Hash, false, i, output.Address, output.Amount, 0})
+	}
+	return rows
+}
+
+//InsertBlocksBulk saves a batch of blocks by streaming them with COPY into a
+//staging table and upserting all of them with one statement
+func (obe *Postgre) InsertBlocksBulk(blocks []hsBC.BlockInfo) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	sqlCreateStaging := `CREATE TEMP TABLE IF NOT EXISTS tmp_blocks
+	(
+		height bigint,
+		hash character varying(256),
+		chainid text,
+		"time" timestamp without time zone,
+		txcounts bigint,
+		proposer_address character varying(64),
+		total_txs bigint,
+		last_block_hash character varying(256),
+		last_commit_hash character varying(256),
+		data_hash character varying(256),
+		validators_hash character varying(256),
+		next_validators_hash character varying(256),
+		consensus_hash character varying(256),
+		app_hash character varying(256),
+		last_results_hash character varying(256),
+		evidence_hash character varying(256)
+	) ON COMMIT DROP;`
+
+	sqlUpsert := `INSERT INTO blocks (height, hash, chainID, time, txcounts, duration, proposer_address,
+		total_txs, last_block_hash, last_commit_hash, data_hash, validators_hash, next_validators_hash,
+		consensus_hash, app_hash, last_results_hash, evidence_hash)
+	SELECT height, hash, chainid, time, txcounts, 0, proposer_address,
+		total_txs, last_block_hash, last_commit_hash, data_hash, validators_hash, next_validators_hash,
+		consensus_hash, app_hash, last_results_hash, evidence_hash FROM tmp_blocks
+	ON CONFLICT (height) DO UPDATE
+	SET ` + blocksUpsertSet + `;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		dbTx := txAdapter.objTx
+		if err := prepareStaging(dbTx, sqlCreateStaging, "tmp_blocks"); err != nil {
+			return err
+		}
+
+		stmt, err := dbTx.Prepare(pq.CopyIn("tmp_blocks", blockCopyColumns...))
+		if err != nil {
+			return err
+		}
+
+		for i := range blocks {
+			if _, err := stmt.Exec(blockCopyRow(&blocks[i])...); err != nil {
+				stmt.Close()
+				return err
+			}
+		}
+
+		if err := closeCopy(stmt); err != nil {
+			return err
+		}
+
+		_, err = dbTx.Exec(sqlUpsert)
+		return err
+	})
+}
+
+//InsertTxsBulk saves a batch of transactions with their inputs and outputs by streaming them
+//with COPY into staging tables, already saved transactions are skipped and num_txs of user
+//accounts is increased with one set-based statement
+func (obe *Postgre) InsertTxsBulk(txs []hsBC.Transaction) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	sqlCreateStaging := `CREATE TEMP TABLE IF NOT EXISTS tmp_transactions
+	(
+		seq integer,
+		block_id integer,
+		txhash character varying(256),
+		fee bigint,
+		gas_limit bigint,
+		data character varying,
+		addr_from character varying(64),
+		addr_to character varying(64),
+		amount bigint,
+		tx_type character varying(32)
+	) ON COMMIT DROP;`
+
+	sqlCreateIOStaging := `CREATE TEMP TABLE IF NOT EXISTS tmp_tx_io
+	(
+		txhash character varying(256),
+		is_input boolean,
+		idx integer,
+		address character varying(64),
+		amount bigint,
+		sequence bigint
+	) ON COMMIT DROP;`
+
+	//inputs and outputs are only saved for transactions that are inserted now, every address
+	//that takes part in a transaction is counted once for it
+	sqlUpsert := `WITH inserted AS
+	(
+		INSERT INTO transactions (block_id, txhash, fee, gas_limit, data, addr_from, addr_to, amount, tx_type)
+		SELECT block_id, txhash, fee, gas_limit, data, addr_from, addr_to, amount, tx_type FROM tmp_transactions
+		ORDER BY seq
+		ON CONFLICT (txhash) DO NOTHING
+		RETURNING id, txhash, addr_from, addr_to
+	), inputs AS
+	(
+		INSERT INTO tx_inputs (tx_id, idx, address, amount, sequence)
+		SELECT i.id, io.idx, io.address, io.amount, io.sequence FROM tmp_tx_io io
+		JOIN inserted i ON i.txhash = io.txhash
+		WHERE io.is_input
+	), outputs AS
+	(
+		INSERT INTO tx_outputs (tx_id, idx, address, amount)
+		SELECT i.id, io.idx, io.address, io.amount FROM tmp_tx_io io
+		JOIN inserted i ON i.txhash = io.txhash
+		WHERE NOT io.is_input
+	)
+	INSERT INTO useraccounts (address, num_txs)
+	SELECT address, COUNT(*) FROM
+	(
+		SELECT id as tx_id, addr_from as address FROM inserted WHERE addr_from<>''
+		UNION
+		SELECT id, addr_to FROM inserted WHERE addr_to<>''
+		UNION
+		SELECT i.id, io.address FROM tmp_tx_io io JOIN inserted i ON i.txhash = io.txhash WHERE io.address<>''
+	) tblAddresses
+	GROUP BY address
+	ON CONFLICT (address) DO UPDATE
+	SET num_txs = useraccounts.num_txs + EXCLUDED.num_txs;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		dbTx := txAdapter.objTx
+		if err := prepareStaging(dbTx, sqlCreateStaging, "tmp_transactions"); err != nil {
+			return err
+		}
+
+		stmt, err := dbTx.Prepare(pq.CopyIn("tmp_transactions", txCopyColumns...))
+		if err != nil {
+			return err
+		}
+
+		for i := range txs {
+			if _, err := stmt.Exec(txCopyRow(i, &txs[i])...); err != nil {
+				stmt.Close()
+				return err
+			}
+		}
+
+		if err := closeCopy(stmt); err != nil {
+			return err
+		}
+
+		if err := prepareStaging(dbTx, sqlCreateIOStaging, "tmp_tx_io"); err != nil {
+			return err
+		}
+
+		stmtIO, err := dbTx.Prepare(pq.CopyIn("tmp_tx_io", txIOCopyColumns...))
+		if err != nil {
+			return err
+		}
+
+		for i := range txs {
+			for _, row := range txIOCopyRows(&txs[i]) {
+				if _, err := stmtIO.Exec(row...); err != nil {
+					stmtIO.Close()
+					return err
+				}
+			}
+		}
+
+		if err := closeCopy(stmtIO); err != nil {
+			return err
+		}
+
+		_, err = dbTx.Exec(sqlUpsert)
+		return err
+	})
+}
+
+//prepareStaging creates an empty staging table that is dropped on commit
+func prepareStaging(dbTx *sql.Tx, sqlCreate string, table string) error {
+	if _, err := dbTx.Exec(sqlCreate); err != nil {
+		return err
+	}
+	_, err := dbTx.Exec("TRUNCATE " + table + ";")
+	return err
+}
+
+//closeCopy flushes buffered rows of COPY statement and closes it
+func closeCopy(stmt *sql.Stmt) error {
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCopyRows(t *testing.T) {
+	block := &hsBC.BlockInfo{ChainID: "C1", Height: 5, BlockHash: "H5", Time: "2020-01-01T00:00:00Z", NumTxs: 1,
+		TotalTxs: 9, ProposerAddress: "V1", LastBlockHash: "H4", EvidenceHash: "E5"}
+	row := blockCopyRow(block)
+	require.Len(t, row, len(blockCopyColumns))
+	require.Equal(t, []interface{}{int64(5), "H5", "C1", "2020-01-01T00:00:00Z", int64(1), "V1", int64(9), "H4"}, row[:8])
+	require.Equal(t, "E5", row[len(row)-1])
+
+	tx := &hsBC.Transaction{Type: "SendTx", BlockID: 5, Hash: "T1", From: "A1", To: "B1", Amount: 10, Fee: 1, GasLimit: 2, Data: "D",
+		Inputs:  []hsBC.TxIO{{Address: "A1", Amount: 6, Sequence: 3}, {Address: "A2", Amount: 5, Sequence: 1}},
+		Outputs: []hsBC.TxIO{{Address: "B1", Amount: 10}}}
+	row = txCopyRow(7, tx)
+	require.Len(t, row, len(txCopyColumns))
+	require.Equal(t, []interface{}{7, int64(5), "T1", uint64(1), uint64(2), "D", "A1", "B1", uint64(10), "SendTx"}, row)
+
+	//indexes of inputs and outputs start from zero each
+	require.Equal(t, [][]interface{}{
+		{"T1", true, 0, "A1", uint64(6), uint64(3)},
+		{"T1", true, 1, "A2", uint64(5), uint64(1)},
+		{"T1", false, 0, "B1", uint64(10), 0},
+	}, txIOCopyRows(tx))
+	for _, row := range txIOCopyRows(tx) {
+		require.Len(t, row, len(txIOCopyColumns))
+	}
+	require.Empty(t, txIOCopyRows(&hsBC.Transaction{Hash: "T2"}))
+}
+
+func TestBulkInsertsSkipSavedTxs(t *testing.T) {
+	obe := connectTestDB(t, "bulk_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	blocks := []hsBC.BlockInfo{
+		{ChainID: "C1", Height: 1, BlockHash: "H1", Time: "2020-01-01T00:00:00Z", NumTxs: 1},
+		{ChainID: "C1", Height: 2, BlockHash: "H2", Time: "2020-01-01T00:00:01Z", NumTxs: 1},
+	}
+	txs := []hsBC.Transaction{
+		{Type: "SendTx", BlockID: 1, Hash: "T1", Inputs: []hsBC.TxIO{{Address: "A1", Amount: 5}}, Outputs: []hsBC.TxIO{{Address: "B1", Amount: 5}}},
+		{Type: "CallTx", BlockID: 2, Hash: "T2", From: "A1", To: "C1", Amount: 3},
+	}
+
+	//second batch is a retry of a batch that is saved before
+	for i := 0; i < 2; i++ {
+		require.NoError(t, obe.InsertBlocksBulk(blocks))
+		require.NoError(t, obe.InsertTxsBulk(txs))
+	}
+
+	count, err := obe.GetBlocksCount()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+	count, err = obe.GetTxsCount()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+
+	account, err := obe.GetUserAccount("A1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), account.NumTxs)
+	inputs, outputs, err := obe.GetTxInputsOutputs("T1")
+	require.NoError(t, err)
+	require.Len(t, inputs, 1)
+	require.Len(t, outputs, 1)
+}
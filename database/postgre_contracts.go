@@ -0,0 +1,111 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+)
+
+//contractsQuery selects contracts with their creation time, ABI state and number of transactions
+const contractsQuery = `SELECT c.address, c.creator, c.tx_id, c.txhash, c.height, b.time,
+	coalesce(c.code_hash, ''), a.address IS NOT NULL, coalesce(u.num_txs, 0)
+FROM contracts c
+JOIN blocks b ON b.height = c.height
+LEFT JOIN abis a ON a.address = c.address
+LEFT JOIN useraccounts u ON u.address = c.address`
+
+//InsertContracts saves deployed contracts of saved transactions, saved contracts are skipped
+func (obe *Postgre) InsertContracts(contracts []hsBC.Contract) error {
+	if len(contracts) == 0 {
+		return nil
+	}
+
+	sqlStatement := `INSERT INTO contracts (address, tx_id, txhash, creator, height, code_hash)
+	SELECT $1, id, txhash, $3, $4, $5 FROM transactions WHERE txhash=$2
+	ON CONFLICT (address) DO NOTHING;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		for _, c := range contracts {
+			_, err := conn.Exec(sqlStatement, c.Address, c.TxHash, c.Creator, c.Height, c.CodeHash)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//GetContracts returns contracts that match filter, latest contract first
+func (obe *Postgre) GetContracts(filter ContractFilter) ([]Contract, error) {
+	sqlStatement, args := contractsListQuery(filter)
+
+	rows, err := obe.conn().Query(sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contracts := make([]Contract, 0)
+	for rows.Next() {
+		c, err := scanContract(rows)
+		if err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, *c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return contracts, nil
+}
+
+//contractsListQuery builds query of GetContracts and its arguments, first page starts from latest contract
+func contractsListQuery(filter ContractFilter) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Creator != "" {
+		addCondition("c.creator=$%d", filter.Creator)
+	}
+
+	beforeHeight, beforeTxID := filter.BeforeHeight, filter.BeforeTxID
+	if beforeHeight <= 0 {
+		beforeHeight, beforeTxID = math.MaxInt64, math.MaxInt32
+	}
+	args = append(args, beforeHeight, beforeTxID)
+	conditions = append(conditions, fmt.Sprintf("(c.height, c.tx_id) < ($%d, $%d)", len(args)-1, len(args)))
+
+	args = append(args, filter.Limit)
+	sqlStatement := fmt.Sprintf(`%s
+	WHERE %s
+	ORDER BY c.height DESC, c.tx_id DESC
+	LIMIT $%d;`, contractsQuery, strings.Join(conditions, " AND "), len(args))
+
+	return sqlStatement, args
+}
+
+//GetContract returns a contract details
+func (obe *Postgre) GetContract(address string) (*Contract, error) {
+	sqlStatement := contractsQuery + `
+	WHERE c.address=$1;`
+
+	return scanContract(obe.conn().QueryRow(sqlStatement, address))
+}
+
+func scanContract(row rowScanner) (*Contract, error) {
+	var c Contract
+	err := row.Scan(&c.Address, &c.Creator, &c.TxID, &c.TxHash, &c.Height, &c.Time, &c.CodeHash, &c.HasABI, &c.NumTxs)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
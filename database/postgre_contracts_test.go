@@ -0,0 +1,67 @@
+package database
+
+import (
+	"math"
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractsListQuery(t *testing.T) {
+	//first page starts before any contract
+	sqlStatement, args := contractsListQuery(ContractFilter{Limit: 10})
+	require.Contains(t, sqlStatement, "WHERE (c.height, c.tx_id) < ($1, $2)")
+	require.Contains(t, sqlStatement, "ORDER BY c.height DESC, c.tx_id DESC")
+	require.Contains(t, sqlStatement, "LIMIT $3;")
+	require.Equal(t, []interface{}{int64(math.MaxInt64), uint64(math.MaxInt32), uint64(10)}, args)
+
+	sqlStatement, args = contractsListQuery(ContractFilter{Creator: "A1", BeforeHeight: 9, BeforeTxID: 4, Limit: 10})
+	require.Contains(t, sqlStatement, "WHERE c.creator=$1 AND (c.height, c.tx_id) < ($2, $3)")
+	require.Contains(t, sqlStatement, "LIMIT $4;")
+	require.Equal(t, []interface{}{"A1", int64(9), uint64(4), uint64(10)}, args)
+}
+
+func TestInsertContracts(t *testing.T) {
+	obe := connectTestDB(t, "contracts_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	require.NoError(t, obe.InsertBlocksBulk([]hsBC.BlockInfo{
+		{ChainID: "C1", Height: 5, BlockHash: "H5", Time: "2020-01-01T00:00:00Z", NumTxs: 1},
+		{ChainID: "C1", Height: 6, BlockHash: "H6", Time: "2020-01-01T00:00:01Z", NumTxs: 1},
+	}))
+	require.NoError(t, obe.InsertTxsBulk([]hsBC.Transaction{
+		{Type: "CallTx", BlockID: 5, Hash: "T1", From: "A1"},
+		{Type: "CallTx", BlockID: 6, Hash: "T2", From: "A2"},
+	}))
+
+	contracts := []hsBC.Contract{
+		{Address: "C1", Creator: "A1", TxHash: "T1", Height: 5, CodeHash: "K1"},
+		{Address: "C2", Creator: "A2", TxHash: "T2", Height: 6},
+		//contracts of txs that are not saved are skipped
+		{Address: "C3", Creator: "A3", TxHash: "T3", Height: 7},
+	}
+	require.NoError(t, obe.InsertContracts(contracts))
+	require.NoError(t, obe.InsertContracts(contracts))
+
+	all, err := obe.GetContracts(ContractFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	require.Equal(t, "C2", all[0].Address)
+	require.Equal(t, "C1", all[1].Address)
+	require.Equal(t, "K1", all[1].CodeHash)
+	require.False(t, all[1].HasABI)
+
+	page, err := obe.GetContracts(ContractFilter{BeforeHeight: all[0].Height, BeforeTxID: all[0].TxID, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, all[1:], page)
+
+	byCreator, err := obe.GetContracts(ContractFilter{Creator: "A2", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, all[:1], byCreator)
+
+	contract, err := obe.GetContract("C1")
+	require.NoError(t, err)
+	require.Equal(t, all[1], *contract)
+}
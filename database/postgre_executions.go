@@ -0,0 +1,221 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/lib/pq"
+)
+
+//InsertTxExecutions saves execution results, events and logs of transactions that are already saved,
+//executions that are saved before are skipped. Executions should be sorted and all executions of
+//a block should be passed together, so index of logs in their block can be counted
+func (obe *Postgre) InsertTxExecutions(execs []hsBC.TxExecution) error {
+	if len(execs) == 0 {
+		return nil
+	}
+
+	sqlExecution := `INSERT INTO tx_executions (tx_id, succeeded, gas_used, return_value, exception_code, exception,
+		creates_contract, contract_address)
+	SELECT id, $2, $3, $4, $5, $6, $7, $8 FROM transactions WHERE txhash=$1
+	ON CONFLICT (tx_id) DO NOTHING
+	RETURNING tx_id;`
+
+	sqlEvent := `INSERT INTO tx_events (tx_id, idx, event_type, event_id, data)
+	VALUES ($1, $2, $3, $4, $5);`
+
+	sqlLog := `INSERT INTO logs (tx_id, height, txhash, log_index, address, topic0, topic1, topic2, topic3, data)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		firstLogIndexes := logIndexes(execs)
+		for i, exec := range execs {
+			var txID int
+			err := conn.QueryRow(sqlExecution, exec.TxHash, exec.Succeeded, exec.GasUsed, exec.Return, exec.ExceptionCode,
+				exec.Exception, exec.CreatesContract, exec.ContractAddress).Scan(&txID)
+			if err == sql.ErrNoRows {
+				//already saved or tx is not saved
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			logIndex := firstLogIndexes[i]
+			for _, event := range exec.Events {
+				if _, err := conn.Exec(sqlEvent, txID, event.Index, event.EventType, event.EventID, event.Data); err != nil {
+					return err
+				}
+
+				if !isLogEvent(event) {
+					continue
+				}
+
+				topics := logTopics(event.Topics)
+				_, err := conn.Exec(sqlLog, txID, exec.Height, exec.TxHash, logIndex, event.Address,
+					topics[0], topics[1], topics[2], topics[3], event.LogData)
+				if err != nil {
+					return err
+				}
+				logIndex++
+			}
+		}
+		return nil
+	})
+}
+
+//isLogEvent checks whether event is an EVM log
+func isLogEvent(event hsBC.TxEvent) bool {
+	return event.Address != "" || len(event.Topics) > 0
+}
+
+//logIndexes returns index of first log of each execution in its block. Logs are counted even
+//for executions that are skipped as saved before, so indexes stay the same
+func logIndexes(execs []hsBC.TxExecution) []int64 {
+	indexes := make([]int64, len(execs))
+	next := make(map[int64]int64)
+	for i, exec := range execs {
+		indexes[i] = next[exec.Height]
+		for _, event := range exec.Events {
+			if isLogEvent(event) {
+				next[exec.Height]++
+			}
+		}
+	}
+	return indexes
+}
+
+//logTopics returns topic columns of a log, topics that are not set are null
+func logTopics(topics []string) [4]sql.NullString {
+	var columns [4]sql.NullString
+	for i := 0; i < len(topics) && i < len(columns); i++ {
+		columns[i] = sql.NullString{String: strings.ToUpper(topics[i]), Valid: true}
+	}
+	return columns
+}
+
+//GetLogs returns logs that match filter in order of height and log index
+func (obe *Postgre) GetLogs(filter LogFilter) ([]Log, error) {
+	sqlStatement, args := logsQuery(filter)
+
+	rows, err := obe.conn().Query(sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := make([]Log, 0)
+	for rows.Next() {
+		var l Log
+		var topics [4]string
+		err := rows.Scan(&l.Height, &l.TxHash, &l.LogIndex, &l.Address, &topics[0], &topics[1], &topics[2], &topics[3], &l.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		l.Topics = make([]string, 0, len(topics))
+		for _, topic := range topics {
+			if topic == "" {
+				break
+			}
+			l.Topics = append(l.Topics, topic)
+		}
+
+		logs = append(logs, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+//logsQuery builds query of GetLogs and its arguments, only conditions of set filters are added
+func logsQuery(filter LogFilter) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.TxHash != "" {
+		addCondition("txhash=$%d", filter.TxHash)
+	}
+	if len(filter.Addresses) > 0 {
+		addCondition("address = ANY($%d)", pq.Array(filter.Addresses))
+	}
+	for i, topics := range filter.Topics {
+		if len(topics) > 0 {
+			addCondition(fmt.Sprintf("topic%d = ANY($%%d)", i), pq.Array(topics))
+		}
+	}
+	if filter.FromHeight > 0 {
+		addCondition("height>=$%d", filter.FromHeight)
+	}
+	if filter.ToHeight > 0 {
+		addCondition("height<=$%d", filter.ToHeight)
+	}
+
+	args = append(args, filter.AfterHeight, filter.AfterIndex)
+	conditions = append(conditions, fmt.Sprintf("(height, log_index) > ($%d, $%d)", len(args)-1, len(args)))
+
+	args = append(args, filter.Limit)
+	sqlStatement := fmt.Sprintf(`SELECT height, txhash, log_index, address,
+		coalesce(topic0, ''), coalesce(topic1, ''), coalesce(topic2, ''), coalesce(topic3, ''), coalesce(data, '')
+	FROM logs
+	WHERE %s
+	ORDER BY height, log_index
+	LIMIT $%d;`, strings.Join(conditions, " AND "), len(args))
+
+	return sqlStatement, args
+}
+
+//GetTxExecution returns execution result and events of a transaction or nil if it is not saved
+func (obe *Postgre) GetTxExecution(hash string) (*hsBC.TxExecution, error) {
+	sqlExecution := `SELECT t.id, t.txhash, t.block_id, e.succeeded, e.gas_used, coalesce(e.return_value, ''),
+		e.exception_code, coalesce(e.exception, ''), e.creates_contract, coalesce(e.contract_address, '')
+	FROM tx_executions e
+	JOIN transactions t ON t.id = e.tx_id
+	WHERE t.txhash=$1;`
+
+	sqlEvents := `SELECT idx, event_type, coalesce(event_id, ''), coalesce(data, '') FROM tx_events
+	WHERE tx_id=$1
+	ORDER BY idx;`
+
+	var txID int
+	var exec hsBC.TxExecution
+	err := obe.conn().QueryRow(sqlExecution, hash).Scan(&txID, &exec.TxHash, &exec.Height, &exec.Succeeded, &exec.GasUsed,
+		&exec.Return, &exec.ExceptionCode, &exec.Exception, &exec.CreatesContract, &exec.ContractAddress)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, nil
+	case nil:
+	default:
+		return nil, err
+	}
+
+	rows, err := obe.conn().Query(sqlEvents, txID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exec.Events = make([]hsBC.TxEvent, 0)
+	for rows.Next() {
+		var event hsBC.TxEvent
+		if err := rows.Scan(&event.Index, &event.EventType, &event.EventID, &event.Data); err != nil {
+			return nil, err
+		}
+		exec.Events = append(exec.Events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &exec, nil
+}
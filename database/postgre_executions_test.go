@@ -0,0 +1,99 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogIndexes(t *testing.T) {
+	log := hsBC.TxEvent{EventType: "Log", Address: "C1"}
+	call := hsBC.TxEvent{EventType: "Call"}
+	execs := []hsBC.TxExecution{
+		{Height: 5, TxHash: "T1", Events: []hsBC.TxEvent{log, call, log}},
+		{Height: 5, TxHash: "T2", Events: []hsBC.TxEvent{call}},
+		{Height: 5, TxHash: "T3", Events: []hsBC.TxEvent{{EventType: "Log", Topics: []string{"A"}}}},
+		{Height: 6, TxHash: "T4", Events: []hsBC.TxEvent{log}},
+		{Height: 6, TxHash: "T5", Events: []hsBC.TxEvent{log}},
+	}
+
+	//each block starts from zero
+	require.Equal(t, []int64{0, 2, 2, 0, 1}, logIndexes(execs))
+	require.Empty(t, logIndexes(nil))
+}
+
+func TestLogTopics(t *testing.T) {
+	topics := logTopics([]string{"ab", "CD"})
+	require.Equal(t, [4]sql.NullString{{String: "AB", Valid: true}, {String: "CD", Valid: true}}, topics)
+
+	//an EVM log has at most four topics
+	topics = logTopics([]string{"1", "2", "3", "4", "5"})
+	require.Equal(t, "4", topics[3].String)
+	require.Equal(t, [4]sql.NullString{}, logTopics(nil))
+}
+
+func TestLogsQuery(t *testing.T) {
+	sqlStatement, args := logsQuery(LogFilter{Limit: 10})
+	require.Contains(t, sqlStatement, "WHERE (height, log_index) > ($1, $2)")
+	require.Contains(t, sqlStatement, "LIMIT $3;")
+	require.Equal(t, []interface{}{int64(0), int64(0), uint64(10)}, args)
+
+	filter := LogFilter{TxHash: "T1", Addresses: []string{"C1", "C2"}, FromHeight: 5, ToHeight: 9,
+		AfterHeight: 6, AfterIndex: 2, Limit: 10}
+	filter.Topics[2] = []string{"AB"}
+	sqlStatement, args = logsQuery(filter)
+	require.Contains(t, sqlStatement, "WHERE txhash=$1 AND address = ANY($2) AND topic2 = ANY($3) AND "+
+		"height>=$4 AND height<=$5 AND (height, log_index) > ($6, $7)")
+	require.Contains(t, sqlStatement, "LIMIT $8;")
+	require.Equal(t, []interface{}{"T1", pq.Array([]string{"C1", "C2"}), pq.Array([]string{"AB"}),
+		int64(5), int64(9), int64(6), int64(2), uint64(10)}, args)
+}
+
+func TestInsertTxExecutionsIndexesLogs(t *testing.T) {
+	obe := connectTestDB(t, "logs_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	require.NoError(t, obe.InsertBlocksBulk([]hsBC.BlockInfo{{ChainID: "C1", Height: 5, BlockHash: "H5", Time: "2020-01-01T00:00:00Z", NumTxs: 2}}))
+	require.NoError(t, obe.InsertTxsBulk([]hsBC.Transaction{
+		{Type: "CallTx", BlockID: 5, Hash: "T1", From: "A1", To: "C1"},
+		{Type: "CallTx", BlockID: 5, Hash: "T2", From: "A1", To: "C2"},
+	}))
+
+	execs := []hsBC.TxExecution{
+		{Height: 5, TxHash: "T1", Succeeded: true, Events: []hsBC.TxEvent{
+			{Index: 0, EventType: "Call"},
+			{Index: 1, EventType: "Log", Address: "C1", Topics: []string{"ab", "01"}, LogData: "D1"},
+		}},
+		{Height: 5, TxHash: "T2", Succeeded: true, Events: []hsBC.TxEvent{
+			{Index: 0, EventType: "Log", Address: "C2", Topics: []string{"AB"}, LogData: "D2"},
+		}},
+	}
+
+	//executions that are saved before are skipped
+	require.NoError(t, obe.InsertTxExecutions(execs))
+	require.NoError(t, obe.InsertTxExecutions(execs))
+
+	logs, err := obe.GetLogs(LogFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []Log{
+		{Height: 5, TxHash: "T1", LogIndex: 0, Address: "C1", Topics: []string{"AB", "01"}, Data: "D1"},
+		{Height: 5, TxHash: "T2", LogIndex: 1, Address: "C2", Topics: []string{"AB"}, Data: "D2"},
+	}, logs)
+
+	filter := LogFilter{Limit: 10}
+	filter.Topics[1] = []string{"01"}
+	logs, err = obe.GetLogs(filter)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "T1", logs[0].TxHash)
+
+	//next page starts after last log of previous page
+	logs, err = obe.GetLogs(LogFilter{AfterHeight: 5, AfterIndex: 0, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "T2", logs[0].TxHash)
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"fmt"
+)
+
+//SchemaVersion returns version of last applied migration, it is zero for an empty database
+func (obe *Postgre) SchemaVersion() (int, error) {
+	sqlCreate := `CREATE TABLE IF NOT EXISTS schema_version
+	(
+		version integer NOT NULL,
+		name text NOT NULL,
+		applied_at timestamp without time zone DEFAULT now() NOT NULL,
+		CONSTRAINT schema_version_pkey PRIMARY KEY (version)
+	);`
+
+	if _, err := obe.conn().Exec(sqlCreate); err != nil {
+		return 0, err
+	}
+
+	sqlStatement := `SELECT coalesce(MAX(version), 0) as version FROM schema_version;`
+
+	var version int
+	err := obe.conn().QueryRow(sqlStatement).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+//Migrate applies all migrations that are not applied yet
+func (obe *Postgre) Migrate() error {
+	return obe.MigrateTo(LatestSchemaVersion())
+}
+
+//MigrateTo applies up or down migrations until schema reaches given version
+func (obe *Postgre) MigrateTo(version int) error {
+	if version < 0 || version > LatestSchemaVersion() {
+		return fmt.Errorf("schema version %d is out of range (max is %d)", version, LatestSchemaVersion())
+	}
+
+	current, err := obe.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current || m.version > version {
+			continue
+		}
+
+		println("applying migration", m.version, "("+m.name+")...")
+		err := obe.runInTx(func(txAdapter *Postgre) error {
+			if _, err := txAdapter.conn().Exec(m.up); err != nil {
+				return err
+			}
+			_, err := txAdapter.conn().Exec(`INSERT INTO schema_version (version, name) VALUES ($1, $2);`, m.version, m.name)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %s", m.version, m.name, err.Error())
+		}
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.version > current || m.version <= version {
+			continue
+		}
+
+		println("reverting migration", m.version, "("+m.name+")...")
+		err := obe.runInTx(func(txAdapter *Postgre) error {
+			if _, err := txAdapter.conn().Exec(m.down); err != nil {
+				return err
+			}
+			_, err := txAdapter.conn().Exec(`DELETE FROM schema_version WHERE version = $1;`, m.version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed: %s", m.version, m.name, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	config "github.com/BurrowBlocks/config"
+	"github.com/stretchr/testify/require"
+)
+
+//testDSNEnv names environment variable with connection string of a postgres server that tests may
+//create and drop databases on, in key=value form. Tests that need postgres are skipped when it is not set
+const testDSNEnv = "BURROWBLOCKS_TEST_DSN"
+
+//connectTestDB creates an empty database on server of testDSNEnv and connects to it without
+//migrating. Database is dropped when test finishes
+func connectTestDB(t *testing.T, name string) *Postgre {
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skip(testDSNEnv + " is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	require.NoError(t, admin.Ping())
+
+	dbName := "burrowblocks_test_" + name
+	_, err = admin.Exec(`DROP DATABASE IF EXISTS "` + dbName + `";`)
+	require.NoError(t, err)
+	_, err = admin.Exec(`CREATE DATABASE "` + dbName + `";`)
+	require.NoError(t, err)
+
+	conn, err := sql.Open("postgres", dsn+" dbname="+dbName)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		admin.Exec(`DROP DATABASE IF EXISTS "` + dbName + `";`)
+		admin.Close()
+	})
+	require.NoError(t, conn.Ping())
+
+	dbConf := config.DefaultDataBaseConfig()
+	dbConf.DBName = dbName
+	dbConf.AutoMigrate = false
+	return &Postgre{Config: &config.Config{DataBase: dbConf}, ObjDB: conn}
+}
+
+//insertTestTxs saves transactions with a block for each height that they are in
+func insertTestTxs(t *testing.T, obe *Postgre, txs []hsBC.Transaction) {
+	counts := make(map[int64]int64)
+	heights := make([]int64, 0)
+	for _, tx := range txs {
+		if counts[tx.BlockID] == 0 {
+			heights = append(heights, tx.BlockID)
+		}
+		counts[tx.BlockID]++
+	}
+
+	blocks := make([]hsBC.BlockInfo, 0, len(heights))
+	for _, height := range heights {
+		blocks = append(blocks, hsBC.BlockInfo{ChainID: "C1", Height: height, BlockHash: fmt.Sprintf("H%d", height),
+			Time: "2020-01-01T00:00:00Z", NumTxs: counts[height]})
+	}
+	require.NoError(t, obe.InsertBlocksBulk(blocks))
+	require.NoError(t, obe.InsertTxsBulk(txs))
+}
+
+func TestMigrateLegacyDuplicates(t *testing.T) {
+	obe := connectTestDB(t, "migrate_test")
+	defer obe.Disconnect()
+
+	//tables of old dump have no keys, so same block, tx and account could be saved twice
+	legacy := `
+	CREATE TABLE blocks (
+		height bigint NOT NULL,
+		hash character varying(256),
+		chainid text,
+		"time" timestamp without time zone,
+		txcounts bigint,
+		duration bigint DEFAULT 0
+	);
+	CREATE TABLE transactions (
+		id serial NOT NULL,
+		block_id integer,
+		txhash character varying(256),
+		fee bigint,
+		gas_limit bigint,
+		data character varying,
+		addr_from character varying(64),
+		addr_to character varying(64),
+		amount bigint,
+		tx_type character varying(10)
+	);
+	CREATE TABLE useraccounts (
+		id bigserial NOT NULL,
+		address character varying(256) NOT NULL,
+		num_txs bigint DEFAULT 0 NOT NULL
+	);
+
+	INSERT INTO blocks (height, hash, txcounts) VALUES (1, 'H1', 0), (2, 'H2', 1), (2, 'H2', 1), (3, 'H3', 0);
+	INSERT INTO transactions (id, block_id, txhash) VALUES (1, 2, 'T1'), (2, 2, 'T1'), (3, 3, 'T2');
+	INSERT INTO useraccounts (id, address, num_txs) VALUES (1, 'A1', 1), (2, 'A1', 3), (3, 'A2', 1), (3, 'A3', 2);
+	`
+	_, err := obe.ObjDB.Exec(legacy)
+	require.NoError(t, err)
+
+	require.NoError(t, obe.Migrate())
+
+	count := func(sqlStatement string) int {
+		var n int
+		require.NoError(t, obe.ObjDB.QueryRow(sqlStatement).Scan(&n))
+		return n
+	}
+	require.Equal(t, 3, count(`SELECT COUNT(*) FROM blocks;`))
+	require.Equal(t, 2, count(`SELECT COUNT(*) FROM transactions;`))
+	require.Equal(t, 1, count(`SELECT id FROM transactions WHERE txhash='T1';`))
+	require.Equal(t, 3, count(`SELECT COUNT(DISTINCT id) FROM useraccounts;`))
+	require.Equal(t, 3, count(`SELECT num_txs FROM useraccounts WHERE address='A1';`))
+
+	//keys exist, so duplicates are rejected from now on
+	_, err = obe.ObjDB.Exec(`INSERT INTO blocks (height, hash) VALUES (3, 'H3');`)
+	require.Error(t, err)
+	_, err = obe.ObjDB.Exec(`INSERT INTO useraccounts (address) VALUES ('A4');`)
+	require.NoError(t, err)
+
+	version, err := obe.SchemaVersion()
+	require.NoError(t, err)
+	require.Equal(t, LatestSchemaVersion(), version)
+
+	//all migrations can be reverted and applied again
+	require.NoError(t, obe.MigrateTo(0))
+	require.NoError(t, obe.Migrate())
+}
+
+func TestMigrateToOutOfRange(t *testing.T) {
+	//version is checked before database is used
+	obe := &Postgre{}
+	require.Error(t, obe.MigrateTo(-1))
+	require.Error(t, obe.MigrateTo(LatestSchemaVersion()+1))
+}
+
+func TestMigrateStepByStep(t *testing.T) {
+	obe := connectTestDB(t, "migrate_steps_test")
+	defer obe.Disconnect()
+
+	version, err := obe.SchemaVersion()
+	require.NoError(t, err)
+	require.Equal(t, 0, version)
+
+	//every migration is applied, reverted and applied again on its own
+	for v := 1; v <= LatestSchemaVersion(); v++ {
+		require.NoError(t, obe.MigrateTo(v), "up to %d", v)
+		require.NoError(t, obe.MigrateTo(v-1), "down to %d", v-1)
+		require.NoError(t, obe.MigrateTo(v), "up again to %d", v)
+
+		version, err = obe.SchemaVersion()
+		require.NoError(t, err)
+		require.Equal(t, v, version)
+	}
+
+	//migrating to current version does nothing
+	require.NoError(t, obe.Migrate())
+
+	//down migrations of all versions leave no table behind but schema_version
+	require.NoError(t, obe.MigrateTo(0))
+	var tables int
+	err = obe.ObjDB.QueryRow(`SELECT COUNT(*) FROM information_schema.tables
+	WHERE table_schema='public' AND table_name<>'schema_version';`).Scan(&tables)
+	require.NoError(t, err)
+	require.Equal(t, 0, tables)
+}
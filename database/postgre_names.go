@@ -0,0 +1,107 @@
+package database
+
+import (
+	"database/sql"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+)
+
+//namesQuery selects entries of names, entries that expire before last saved block are expired
+const namesQuery = `SELECT n.name, coalesce(n.data, ''), n.owner, n.expires, n.height, n.txhash, n.deleted,
+	n.deleted OR n.expires <= (SELECT coalesce(MAX(height), 0) FROM blocks) as expired
+FROM names n`
+
+//InsertNames saves entries of name registry that are set by saved transactions, saved entries are skipped
+func (obe *Postgre) InsertNames(entries []hsBC.NameEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	sqlStatement := `INSERT INTO names (tx_id, txhash, height, name, data, owner, expires, deleted)
+	SELECT id, txhash, $2, $3, $4, $5, $6, $7 FROM transactions WHERE txhash=$1
+	ON CONFLICT (tx_id) DO NOTHING;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		for _, n := range entries {
+			_, err := conn.Exec(sqlStatement, n.TxHash, n.Height, n.Name, n.Data, n.Owner, n.Expires, n.Deleted)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//GetName returns latest entry of a name or nil if it is never registered
+func (obe *Postgre) GetName(name string) (*Name, error) {
+	sqlStatement := namesQuery + `
+	WHERE n.name=$1
+	ORDER BY n.height DESC, n.tx_id DESC
+	LIMIT 1;`
+
+	n, err := scanName(obe.conn().QueryRow(sqlStatement, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return n, err
+}
+
+//GetNameHistory returns latest entries of a name, latest entry first
+func (obe *Postgre) GetNameHistory(name string, limit uint64) ([]Name, error) {
+	sqlStatement := namesQuery + `
+	WHERE n.name=$1
+	ORDER BY n.height DESC, n.tx_id DESC
+	LIMIT $2;`
+
+	return obe.queryNames(sqlStatement, name, limit)
+}
+
+//GetAccountNames returns names whose latest entry is owned by address and is not deleted,
+//names that will expire sooner come first
+func (obe *Postgre) GetAccountNames(address string) ([]Name, error) {
+	sqlStatement := `SELECT * FROM
+	(
+		SELECT DISTINCT ON (n.name) n.name, coalesce(n.data, ''), n.owner, n.expires, n.height, n.txhash, n.deleted,
+			n.deleted OR n.expires <= (SELECT coalesce(MAX(height), 0) FROM blocks) as expired
+		FROM names n
+		WHERE n.name IN (SELECT name FROM names WHERE owner=$1)
+		ORDER BY n.name, n.height DESC, n.tx_id DESC
+	) latest
+	WHERE owner=$1 AND NOT deleted
+	ORDER BY expires;`
+
+	return obe.queryNames(sqlStatement, address)
+}
+
+func (obe *Postgre) queryNames(sqlStatement string, args ...interface{}) ([]Name, error) {
+	rows, err := obe.conn().Query(sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make([]Name, 0)
+	for rows.Next() {
+		n, err := scanName(rows)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, *n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func scanName(row rowScanner) (*Name, error) {
+	var n Name
+	err := row.Scan(&n.Name, &n.Data, &n.Owner, &n.Expires, &n.Height, &n.TxHash, &n.Deleted, &n.Expired)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertNames(t *testing.T) {
+	obe := connectTestDB(t, "names_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	insertTestTxs(t, obe, []hsBC.Transaction{
+		{Type: "NameTx", BlockID: 5, Hash: "T1", From: "A1"},
+		{Type: "NameTx", BlockID: 6, Hash: "T2", From: "A1"},
+		{Type: "NameTx", BlockID: 7, Hash: "T3", From: "A1"},
+		{Type: "NameTx", BlockID: 8, Hash: "T4", From: "B1"},
+	})
+
+	entries := []hsBC.NameEntry{
+		{Name: "alice", Data: "a", Owner: "A1", Expires: 100, Height: 5, TxHash: "T1"},
+		{Name: "alice", Data: "b", Owner: "A1", Expires: 100, Height: 6, TxHash: "T2"},
+		{Name: "bob", Owner: "A1", Expires: 7, Height: 7, TxHash: "T3"},
+		//taken by other owner after it is expired
+		{Name: "bob", Data: "c", Owner: "B1", Expires: 50, Height: 8, TxHash: "T4"},
+	}
+	require.NoError(t, obe.InsertNames(entries))
+	require.NoError(t, obe.InsertNames(entries))
+
+	name, err := obe.GetName("alice")
+	require.NoError(t, err)
+	require.Equal(t, &Name{Name: "alice", Data: "b", Owner: "A1", Expires: 100, Height: 6, TxHash: "T2"}, name)
+
+	name, err = obe.GetName("carol")
+	require.NoError(t, err)
+	require.Nil(t, name)
+
+	history, err := obe.GetNameHistory("bob", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "B1", history[0].Owner)
+	require.False(t, history[0].Expired)
+	require.True(t, history[1].Expired)
+
+	//names whose latest entry is owned by other account are not listed
+	names, err := obe.GetAccountNames("A1")
+	require.NoError(t, err)
+	require.Len(t, names, 1)
+	require.Equal(t, "alice", names[0].Name)
+}
@@ -0,0 +1,240 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+)
+
+//GetAccountsPage returns user accounts with id greater than afterID
+func (obe *Postgre) GetAccountsPage(afterID uint64, limit uint64) ([]UserAccount, error) {
+
+	sqlStatement := `SELECT u.id, u.address, u.num_txs, coalesce(a.balance, 0) FROM useraccounts u
+	LEFT JOIN accounts a ON a.address = u.address
+	WHERE u.id>$1
+	ORDER BY u.id
+	LIMIT $2;`
+
+	rows, errGetUserAccs := obe.conn().Query(sqlStatement, afterID, limit)
+	if errGetUserAccs != nil {
+		return nil, errGetUserAccs
+	}
+	defer rows.Close()
+
+	accs := make([]UserAccount, 0)
+	for rows.Next() {
+
+		var acc UserAccount
+		if err := rows.Scan(&acc.ID, &acc.Address, &acc.NumTxs, &acc.Balance); err != nil {
+			return nil, err
+		}
+
+		accs = append(accs, acc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accs, nil
+}
+
+//GetAccountTxsPage returns transactions of address that are after given block id and tx id,
+//address may be sender, receiver or any input or output of transaction
+func (obe *Postgre) GetAccountTxsPage(address string, afterBlockID int64, afterID uint64, limit uint64) ([]hsBC.Transaction, error) {
+
+	sqlStatement := `SELECT id,block_id,txhash,fee,gas_limit,data,addr_from,addr_to,amount,tx_type FROM transactions
+	WHERE id IN
+	(
+		(
+			SELECT id FROM transactions
+			WHERE addr_from=$1 AND (block_id, id) > ($2, $3)
+			ORDER BY block_id, id
+			LIMIT $4
+		)
+		UNION
+		(
+			SELECT id FROM transactions
+			WHERE addr_to=$1 AND (block_id, id) > ($2, $3)
+			ORDER BY block_id, id
+			LIMIT $4
+		)
+		UNION
+		(
+			SELECT t.id FROM tx_inputs i JOIN transactions t ON t.id = i.tx_id
+			WHERE i.address=$1 AND (t.block_id, t.id) > ($2, $3)
+			ORDER BY t.block_id, t.id
+			LIMIT $4
+		)
+		UNION
+		(
+			SELECT t.id FROM tx_outputs o JOIN transactions t ON t.id = o.tx_id
+			WHERE o.address=$1 AND (t.block_id, t.id) > ($2, $3)
+			ORDER BY t.block_id, t.id
+			LIMIT $4
+		)
+	)
+	ORDER BY block_id, id
+	LIMIT $4;`
+
+	rows, errGetTxs := obe.conn().Query(sqlStatement, address, afterBlockID, afterID, limit)
+	if errGetTxs != nil {
+		return nil, errGetTxs
+	}
+	defer rows.Close()
+
+	return scanTxsWithID(rows)
+}
+
+//GetLatestTxsPage returns transactions that are before given block id and tx id, latest transaction first
+func (obe *Postgre) GetLatestTxsPage(beforeBlockID int64, beforeID uint64, limit uint64) ([]hsBC.Transaction, error) {
+
+	sqlStatement := `SELECT id,block_id,txhash,fee,gas_limit,data,addr_from,addr_to,amount,tx_type FROM transactions
+	WHERE (block_id, id) < ($1::bigint, $2::bigint)
+	ORDER BY block_id DESC, id DESC
+	LIMIT $3;`
+
+	rows, errGetTxs := obe.conn().Query(sqlStatement, beforeBlockID, beforeID, limit)
+	if errGetTxs != nil {
+		return nil, errGetTxs
+	}
+	defer rows.Close()
+
+	return scanTxsWithID(rows)
+}
+
+//GetBlocksList returns blocks that match filter, it starts after filter.AfterHeight in sort order
+func (obe *Postgre) GetBlocksList(filter BlockFilter) ([]hsBC.Block, error) {
+	sqlStatement, args := blocksListQuery(filter)
+
+	rows, errGetBlocks := obe.conn().Query(sqlStatement, args...)
+	if errGetBlocks != nil {
+		return nil, errGetBlocks
+	}
+	defer rows.Close()
+
+	blocks := make([]hsBC.Block, 0)
+	for rows.Next() {
+
+		b, err := scanBlock(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, *b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+//blocksListQuery builds query of GetBlocksList and its arguments, only conditions of set filters are added
+func blocksListQuery(filter BlockFilter) (string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.MinHeight > 0 {
+		addCondition("height>=$%d", filter.MinHeight)
+	}
+	if filter.MaxHeight > 0 {
+		addCondition("height<=$%d", filter.MaxHeight)
+	}
+	if !filter.FromTime.IsZero() {
+		addCondition(`"time">=$%d`, filter.FromTime)
+	}
+	if !filter.ToTime.IsZero() {
+		addCondition(`"time"<=$%d`, filter.ToTime)
+	}
+	if filter.Proposer != "" {
+		addCondition("proposer_address=$%d", filter.Proposer)
+	}
+	if filter.OnlyWithTxs {
+		conditions = append(conditions, "txcounts>0")
+	}
+
+	order := "DESC"
+	if filter.Ascending {
+		order = "ASC"
+		if filter.AfterHeight > 0 {
+			addCondition("height>$%d", filter.AfterHeight)
+		}
+	} else if filter.AfterHeight > 0 {
+		addCondition("height<$%d", filter.AfterHeight)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	sqlStatement := fmt.Sprintf(`SELECT %s FROM blocks
+	%s
+	ORDER BY height %s
+	LIMIT $%d;`, blockColumns, where, order, len(args))
+
+	return sqlStatement, args
+}
+
+//blockColumns are columns of blocks table that are read by scanBlock, header columns
+//are null for blocks that are saved before they were added
+const blockColumns = `height, hash, chainID, time, txcounts, duration, coalesce(proposer_address, ''),
+	coalesce(total_txs, 0), coalesce(last_block_hash, ''), coalesce(last_commit_hash, ''), coalesce(data_hash, ''),
+	coalesce(validators_hash, ''), coalesce(next_validators_hash, ''), coalesce(consensus_hash, ''),
+	coalesce(app_hash, ''), coalesce(last_results_hash, ''), coalesce(evidence_hash, '')`
+
+//blocksUpsertSet updates all columns of a saved block except duration from EXCLUDED row
+const blocksUpsertSet = `hash = EXCLUDED.hash, chainID = EXCLUDED.chainID, time = EXCLUDED.time, txcounts = EXCLUDED.txcounts,
+		proposer_address = EXCLUDED.proposer_address, total_txs = EXCLUDED.total_txs,
+		last_block_hash = EXCLUDED.last_block_hash, last_commit_hash = EXCLUDED.last_commit_hash,
+		data_hash = EXCLUDED.data_hash, validators_hash = EXCLUDED.validators_hash,
+		next_validators_hash = EXCLUDED.next_validators_hash, consensus_hash = EXCLUDED.consensus_hash,
+		app_hash = EXCLUDED.app_hash, last_results_hash = EXCLUDED.last_results_hash,
+		evidence_hash = EXCLUDED.evidence_hash`
+
+//rowScanner is implemented by both sql.Row and sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+//scanBlock reads a block that is selected with blockColumns
+func scanBlock(row rowScanner) (*hsBC.Block, error) {
+	var b hsBC.Block
+	err := row.Scan(&b.Height, &b.Hash, &b.ChainID, &b.Time, &b.TxCounts, &b.Duration, &b.ProposerAddress,
+		&b.TotalTxs, &b.LastBlockHash, &b.LastCommitHash, &b.DataHash,
+		&b.ValidatorsHash, &b.NextValidatorsHash, &b.ConsensusHash,
+		&b.AppHash, &b.LastResultsHash, &b.EvidenceHash)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+//scanTxsWithID reads transactions from rows that start with transaction id
+func scanTxsWithID(rows *sql.Rows) ([]hsBC.Transaction, error) {
+	txs := make([]hsBC.Transaction, 0)
+	for rows.Next() {
+
+		var txn hsBC.Transaction
+		if err := rows.Scan(&txn.ID, &txn.BlockID, &txn.Hash, &txn.Fee, &txn.GasLimit, &txn.Data, &txn.From, &txn.To, &txn.Amount, &txn.Type); err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, txn)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
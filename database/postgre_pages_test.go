@@ -0,0 +1,71 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocksListQuery(t *testing.T) {
+	//only limit is set when no filter is set
+	sqlStatement, args := blocksListQuery(BlockFilter{Limit: 10})
+	require.NotContains(t, sqlStatement, "WHERE")
+	require.Contains(t, sqlStatement, "ORDER BY height DESC")
+	require.Contains(t, sqlStatement, "LIMIT $1;")
+	require.Equal(t, []interface{}{uint64(10)}, args)
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	sqlStatement, args = blocksListQuery(BlockFilter{MinHeight: 5, MaxHeight: 50, FromTime: from, ToTime: to,
+		Proposer: "V1", OnlyWithTxs: true, Ascending: true, AfterHeight: 7, Limit: 20})
+	require.Contains(t, sqlStatement, `WHERE height>=$1 AND height<=$2 AND "time">=$3 AND "time"<=$4 AND `+
+		`proposer_address=$5 AND txcounts>0 AND height>$6`)
+	require.Contains(t, sqlStatement, "ORDER BY height ASC")
+	require.Contains(t, sqlStatement, "LIMIT $7;")
+	require.Equal(t, []interface{}{int64(5), int64(50), from, to, "V1", int64(7), uint64(20)}, args)
+
+	//cursor of descending pages starts below last height
+	sqlStatement, args = blocksListQuery(BlockFilter{OnlyWithTxs: true, AfterHeight: 7, Limit: 20})
+	require.Contains(t, sqlStatement, "WHERE txcounts>0 AND height<$1")
+	require.Equal(t, []interface{}{int64(7), uint64(20)}, args)
+	require.Equal(t, 1, strings.Count(sqlStatement, "WHERE"))
+}
+
+func TestGetLatestTxsPage(t *testing.T) {
+	obe := connectTestDB(t, "latest_txs_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	txs := make([]hsBC.Transaction, 0)
+	for i := 1; i <= 5; i++ {
+		txs = append(txs, hsBC.Transaction{Type: "SendTx", BlockID: int64(i), Hash: fmt.Sprintf("T%d", i), From: "A1", To: "B1"})
+	}
+	insertTestTxs(t, obe, txs)
+
+	hashes := func(txs []hsBC.Transaction) []string {
+		h := make([]string, 0, len(txs))
+		for _, tx := range txs {
+			h = append(h, tx.Hash)
+		}
+		return h
+	}
+
+	//first page has no cursor, keys before every tx are given instead
+	page, err := obe.GetLatestTxsPage(math.MaxInt32, math.MaxInt32, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"T5", "T4"}, hashes(page))
+
+	page, err = obe.GetLatestTxsPage(page[1].BlockID, page[1].ID, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"T3", "T2"}, hashes(page))
+
+	//keys that do not fit integer columns are still compared
+	page, err = obe.GetLatestTxsPage(math.MaxInt64, math.MaxInt64, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 5)
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"database/sql"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/lib/pq"
+)
+
+//InsertPermissionChanges saves permission changes of saved transactions, saved changes are skipped
+func (obe *Postgre) InsertPermissionChanges(changes []hsBC.PermissionChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	sqlStatement := `INSERT INTO permission_changes (tx_id, idx, txhash, height, address, sender, action, permissions, value, roles)
+	SELECT id, $2, txhash, $3, $4, $5, $6, $7, $8, $9 FROM transactions WHERE txhash=$1
+	ON CONFLICT (tx_id, idx) DO NOTHING;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		for _, c := range changes {
+			_, err := conn.Exec(sqlStatement, c.TxHash, c.Index, c.Height, c.Address, c.Sender, c.Action,
+				permissionsColumn(c.Permissions), valueColumn(c.Value), pq.Array(c.Roles))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//GetPermissionChanges returns latest permission changes of address, latest change first
+func (obe *Postgre) GetPermissionChanges(address string, limit uint64) ([]hsBC.PermissionChange, error) {
+	sqlStatement := `SELECT height, txhash, idx, address, sender, action, permissions, value, roles
+	FROM permission_changes
+	WHERE address=$1
+	ORDER BY height DESC, tx_id DESC, idx DESC
+	LIMIT $2;`
+
+	rows, err := obe.conn().Query(sqlStatement, address, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]hsBC.PermissionChange, 0)
+	for rows.Next() {
+		var c hsBC.PermissionChange
+		var perms int64
+		var value sql.NullBool
+		err := rows.Scan(&c.Height, &c.TxHash, &c.Index, &c.Address, &c.Sender, &c.Action, &perms, &value, pq.Array(&c.Roles))
+		if err != nil {
+			return nil, err
+		}
+
+		c.Permissions = hsBC.PermFlag(perms).Names()
+		c.Value = valueFromColumn(value)
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+//permissionsColumn returns flags of permission names as they are saved
+func permissionsColumn(names []string) int64 {
+	return int64(hsBC.PermFlagFromNames(names))
+}
+
+//valueColumn returns value of a change as it is saved, it is null for changes that do not set a value
+func valueColumn(value *bool) sql.NullBool {
+	if value == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *value, Valid: true}
+}
+
+//valueFromColumn returns value of a saved change, it is nil for changes that do not set a value
+func valueFromColumn(value sql.NullBool) *bool {
+	if !value.Valid {
+		return nil
+	}
+	v := value.Bool
+	return &v
+}
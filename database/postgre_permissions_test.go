@@ -0,0 +1,55 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionColumns(t *testing.T) {
+	require.Equal(t, int64(0), permissionsColumn([]string{}))
+	require.Equal(t, int64(hsBC.PermSend|hsBC.PermIdentify), permissionsColumn([]string{"send", "identify"}))
+	require.Equal(t, []string{"send", "identify"}, hsBC.PermFlag(permissionsColumn([]string{"identify", "send"})).Names())
+
+	yes, no := true, false
+	require.Equal(t, sql.NullBool{}, valueColumn(nil))
+	require.Equal(t, sql.NullBool{Bool: true, Valid: true}, valueColumn(&yes))
+	require.Equal(t, sql.NullBool{Valid: true}, valueColumn(&no))
+
+	require.Nil(t, valueFromColumn(sql.NullBool{}))
+	require.Equal(t, &yes, valueFromColumn(sql.NullBool{Bool: true, Valid: true}))
+	require.Equal(t, &no, valueFromColumn(sql.NullBool{Valid: true}))
+}
+
+func TestInsertPermissionChanges(t *testing.T) {
+	obe := connectTestDB(t, "permissions_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	insertTestTxs(t, obe, []hsBC.Transaction{
+		{Type: "PermsTx", BlockID: 5, Hash: "T1", From: "A1"},
+		{Type: "GovTx", BlockID: 6, Hash: "T2", From: "A1"},
+	})
+
+	yes := true
+	changes := []hsBC.PermissionChange{
+		{Height: 5, TxHash: "T1", Index: 0, Address: "B1", Sender: "A1", Action: hsBC.PermActionSetBase,
+			Permissions: []string{"send", "identify"}, Value: &yes, Roles: []string{}},
+		{Height: 6, TxHash: "T2", Index: 0, Address: "B1", Sender: "A1", Action: hsBC.PermActionAddRole,
+			Permissions: []string{}, Roles: []string{"admin"}},
+		{Height: 6, TxHash: "T2", Index: 1, Address: "B2", Sender: "A1", Action: hsBC.PermActionAddRole,
+			Permissions: []string{}, Roles: []string{"user"}},
+	}
+	require.NoError(t, obe.InsertPermissionChanges(changes))
+	require.NoError(t, obe.InsertPermissionChanges(changes))
+
+	saved, err := obe.GetPermissionChanges("B1", 10)
+	require.NoError(t, err)
+	require.Equal(t, []hsBC.PermissionChange{changes[1], changes[0]}, saved)
+
+	saved, err = obe.GetPermissionChanges("B1", 1)
+	require.NoError(t, err)
+	require.Equal(t, []hsBC.PermissionChange{changes[1]}, saved)
+}
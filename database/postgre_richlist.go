@@ -0,0 +1,69 @@
+package database
+
+import (
+	"fmt"
+)
+
+//RefreshRichList recomputes ranks of accounts in rich list without blocking its readers
+func (obe *Postgre) RefreshRichList() error {
+	sqlStatement := `REFRESH MATERIALIZED VIEW CONCURRENTLY rich_list;`
+
+	_, err := obe.conn().Exec(sqlStatement)
+	return err
+}
+
+//GetRichList returns accounts in order of their rank by balance, txs or volume after given rank
+func (obe *Postgre) GetRichList(order string, afterRank uint64, limit uint64) ([]RichListAccount, error) {
+	rankColumn, err := richListRankColumn(order)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlStatement := fmt.Sprintf(`SELECT %[1]s, address, balance, num_txs, sent, received,
+		(SELECT coalesce(SUM(balance), 0) FROM rich_list) as supply
+	FROM rich_list
+	WHERE %[1]s>$1
+	ORDER BY %[1]s
+	LIMIT $2;`, rankColumn)
+
+	rows, err := obe.conn().Query(sqlStatement, afterRank, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accs := make([]RichListAccount, 0)
+	for rows.Next() {
+		var acc RichListAccount
+		var supply float64
+		if err := rows.Scan(&acc.Rank, &acc.Address, &acc.Balance, &acc.NumTxs, &acc.Sent, &acc.Received, &supply); err != nil {
+			return nil, err
+		}
+
+		acc.Volume = acc.Sent + acc.Received
+		if supply > 0 {
+			acc.SupplyPercent = float64(acc.Balance) * 100 / supply
+		}
+		accs = append(accs, acc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accs, nil
+}
+
+//richListRankColumn returns column of rich list that ranks accounts by order
+func richListRankColumn(order string) (string, error) {
+	switch order {
+	case RichListByBalance:
+		return "balance_rank", nil
+	case RichListByTxs:
+		return "txs_rank", nil
+	case RichListByVolume:
+		return "volume_rank", nil
+	default:
+		return "", fmt.Errorf("unknown rich list order %s", order)
+	}
+}
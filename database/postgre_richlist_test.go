@@ -0,0 +1,61 @@
+package database
+
+import (
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRichListRankColumn(t *testing.T) {
+	for order, column := range map[string]string{
+		RichListByBalance: "balance_rank",
+		RichListByTxs:     "txs_rank",
+		RichListByVolume:  "volume_rank",
+	} {
+		rankColumn, err := richListRankColumn(order)
+		require.NoError(t, err, order)
+		require.Equal(t, column, rankColumn, order)
+	}
+
+	//order is put in query, so unknown orders are rejected
+	_, err := richListRankColumn("balance_rank; DROP TABLE blocks")
+	require.Error(t, err)
+}
+
+func TestRefreshRichList(t *testing.T) {
+	obe := connectTestDB(t, "richlist_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	require.NoError(t, obe.InsertAccount(&hsBC.Account{Address: "A1", Balance: 30}))
+	require.NoError(t, obe.InsertAccount(&hsBC.Account{Address: "B1", Balance: 70}))
+	require.NoError(t, obe.InsertBlocksBulk([]hsBC.BlockInfo{{ChainID: "C1", Height: 5, BlockHash: "H5", Time: "2020-01-01T00:00:00Z", NumTxs: 1}}))
+	require.NoError(t, obe.InsertTxsBulk([]hsBC.Transaction{{Type: "SendTx", BlockID: 5, Hash: "T1",
+		Inputs: []hsBC.TxIO{{Address: "A1", Amount: 20}}, Outputs: []hsBC.TxIO{{Address: "B1", Amount: 20}}}}))
+	require.NoError(t, obe.InsertBalanceChanges([]hsBC.BalanceChange{
+		{Height: 5, TxHash: "T1", Index: 0, Address: "A1", Delta: -20, Reason: hsBC.ReasonInput},
+		{Height: 5, TxHash: "T1", Index: 1, Address: "B1", Delta: 20, Reason: hsBC.ReasonOutput},
+	}))
+
+	//ranks are only changed by refresh
+	accs, err := obe.GetRichList(RichListByBalance, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, accs)
+
+	require.NoError(t, obe.RefreshRichList())
+	accs, err = obe.GetRichList(RichListByBalance, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, []RichListAccount{
+		{Rank: 1, Address: "B1", Balance: 70, NumTxs: 1, Received: 20, Volume: 20, SupplyPercent: 70},
+		{Rank: 2, Address: "A1", Balance: 30, NumTxs: 1, Sent: 20, Volume: 20, SupplyPercent: 30},
+	}, accs)
+
+	accs, err = obe.GetRichList(RichListByVolume, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, accs, 1)
+	require.Equal(t, uint64(2), accs[0].Rank)
+
+	_, err = obe.GetRichList("richest", 0, 10)
+	require.Error(t, err)
+}
@@ -0,0 +1,201 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountTransactionsRange(t *testing.T) {
+	obe := connectTestDB(t, "account_txs_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	//address is sender of some txs and receiver of others, ranges are longer than a v2 page
+	txs := make([]hsBC.Transaction, 0)
+	for i := 1; i <= 250; i++ {
+		from, to := "A1", fmt.Sprintf("B%d", i)
+		if i%2 == 0 {
+			from, to = to, "A1"
+		}
+		txs = append(txs, hsBC.Transaction{Type: "SendTx", BlockID: int64(i), Hash: fmt.Sprintf("T%d", i), From: from, To: to})
+	}
+	insertTestTxs(t, obe, txs)
+
+	tests := []struct {
+		minID uint64
+		maxID uint64
+		first string
+		n     int
+	}{
+		{1, 250, "T1", 250},
+		{0, 10, "T1", 10},
+		{101, 250, "T101", 150},
+		{240, 1000, "T240", 11},
+		{5, 5, "T5", 1},
+		{5, 4, "", 0},
+		{300, 400, "", 0},
+		{2, math.MaxUint64, "T2", 249},
+	}
+
+	for _, test := range tests {
+		page, count, err := obe.GetAccountTransactions("A1", test.minID, test.maxID)
+		require.NoError(t, err)
+		require.Equal(t, uint64(250), count)
+		require.Len(t, page, test.n, "%d to %d", test.minID, test.maxID)
+		if test.n > 0 {
+			require.Equal(t, test.first, page[0].Hash, "%d to %d", test.minID, test.maxID)
+		}
+	}
+}
+
+func TestInsertsAreIdempotent(t *testing.T) {
+	obe := connectTestDB(t, "idempotent_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	state, err := obe.GetSyncState()
+	require.NoError(t, err)
+	require.Nil(t, state)
+
+	block := &hsBC.BlockInfo{ChainID: "C1", Height: 1, BlockHash: "H1", Time: "2020-01-01T00:00:00Z", NumTxs: 1}
+	tx := &hsBC.Transaction{Type: "SendTx", BlockID: 1, Hash: "T1", From: "A1", To: "B1", Amount: 10,
+		Inputs: []hsBC.TxIO{{Address: "A1", Amount: 10}}, Outputs: []hsBC.TxIO{{Address: "B1", Amount: 10}}}
+
+	//a block that is saved again after a crash before checkpoint is updated
+	for i := 0; i < 2; i++ {
+		require.NoError(t, obe.InsertBlock(block))
+		require.NoError(t, obe.InsertTx(tx))
+	}
+
+	blocks, err := obe.GetBlocksCount()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), blocks)
+	txs, err := obe.GetTxsCount()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), txs)
+
+	//accounts are only counted when tx is saved first time
+	account, err := obe.GetUserAccount("A1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), account.NumTxs)
+	inputs, outputs, err := obe.GetTxInputsOutputs("T1")
+	require.NoError(t, err)
+	require.Len(t, inputs, 1)
+	require.Len(t, outputs, 1)
+
+	require.NoError(t, obe.UpdateSyncState("C1", 1))
+	require.NoError(t, obe.UpdateSyncState("C1", 2))
+	state, err = obe.GetSyncState()
+	require.NoError(t, err)
+	require.Equal(t, &SyncState{ChainID: "C1", LastHeight: 2}, state)
+}
+
+func TestRollbackBlocksRestoresCounters(t *testing.T) {
+	obe := connectTestDB(t, "rollback_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	send := func(height int64, hash string, from string, to string) hsBC.Transaction {
+		return hsBC.Transaction{Type: "SendTx", BlockID: height, Hash: hash, From: from, To: to, Amount: 1,
+			Inputs: []hsBC.TxIO{{Address: from, Amount: 1}}, Outputs: []hsBC.TxIO{{Address: to, Amount: 1}}}
+	}
+	insertTestTxs(t, obe, []hsBC.Transaction{
+		send(1, "T1", "A1", "B1"),
+		send(2, "T2", "B1", "A1"),
+		send(3, "T3", "A1", "C1"),
+		send(4, "T4", "C1", "A1"),
+	})
+
+	numTxs := func(address string) uint64 {
+		acc, err := obe.GetUserAccount(address)
+		require.NoError(t, err)
+		return acc.NumTxs
+	}
+	require.Equal(t, uint64(4), numTxs("A1"))
+
+	require.NoError(t, obe.RollbackBlocks(3))
+
+	blocks, err := obe.GetBlocksCount()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), blocks)
+	txs, err := obe.GetTxsCount()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), txs)
+
+	//each removed tx is taken once from each of its participants
+	require.Equal(t, uint64(2), numTxs("A1"))
+	require.Equal(t, uint64(2), numTxs("B1"))
+	_, err = obe.GetUserAccount("C1")
+	require.Equal(t, sql.ErrNoRows, err)
+
+	//blocks of new chain are counted again as they are saved
+	insertTestTxs(t, obe, []hsBC.Transaction{send(3, "T5", "A1", "B1")})
+	require.Equal(t, uint64(3), numTxs("A1"))
+	require.Equal(t, uint64(3), numTxs("B1"))
+}
+
+func TestRollbackDerivedState(t *testing.T) {
+	obe := connectTestDB(t, "rollback_derived_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	send := func(height int64, hash string) hsBC.Transaction {
+		return hsBC.Transaction{Type: "SendTx", BlockID: height, Hash: hash, From: "A1", To: "B1", Amount: 1}
+	}
+	insertTestTxs(t, obe, []hsBC.Transaction{send(2, "T2"), send(3, "T3"), send(4, "T4")})
+	require.NoError(t, obe.SaveGenesisValidators([]hsBC.Validator{{Address: "V1", Power: 10}}))
+	require.NoError(t, obe.SaveGenesisBalances([]hsBC.Account{{Address: "A1", Balance: 100}}))
+
+	for _, h := range []int64{2, 3, 4} {
+		hash := fmt.Sprintf("T%d", h)
+		require.NoError(t, obe.InsertTxExecutions([]hsBC.TxExecution{{TxHash: hash, Height: h, Succeeded: true,
+			Events: []hsBC.TxEvent{{EventType: "Log", Address: "C1"}}}}))
+		require.NoError(t, obe.InsertNames([]hsBC.NameEntry{{Name: "alice", Data: hash, Owner: "A1", Height: h, TxHash: hash}}))
+		require.NoError(t, obe.InsertValidatorPowerChanges([]hsBC.ValidatorPowerChange{{Height: h, TxHash: hash,
+			Address: "V1", Delta: 1, Power: uint64(10 + h)}}))
+		require.NoError(t, obe.InsertBalanceChanges([]hsBC.BalanceChange{{Height: h, TxHash: hash, Address: "A1",
+			Delta: -1, Reason: hsBC.ReasonInput}}))
+	}
+	require.NoError(t, obe.ReconcileValidators([]hsBC.Validator{{Address: "V1", Power: 20}}, 4))
+	require.NoError(t, obe.UpdateSyncState("C1", 4))
+	require.NoError(t, obe.UpdateDerivedHeight(4))
+
+	require.NoError(t, obe.RollbackDerivedState(3))
+
+	count := func(table string) int {
+		var n int
+		require.NoError(t, obe.conn().QueryRow(`SELECT COUNT(*) FROM `+table+`;`).Scan(&n))
+		return n
+	}
+	require.Equal(t, 1, count("tx_executions"))
+	require.Equal(t, 1, count("logs"))
+	require.Equal(t, 1, count("validator_power_changes"))
+	//genesis balance and set are kept
+	require.Equal(t, 2, count("balance_changes"))
+	require.Equal(t, 1, count("validator_set_changes"))
+	//blocks and txs are not derived state
+	require.Equal(t, 3, count("transactions"))
+
+	name, err := obe.GetName("alice")
+	require.NoError(t, err)
+	require.Equal(t, "T2", name.Data)
+	validator, err := obe.GetValidator("V1")
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), validator.Power)
+
+	state, err := obe.GetSyncState()
+	require.NoError(t, err)
+	require.Equal(t, &SyncState{ChainID: "C1", LastHeight: 4, DerivedHeight: 2}, state)
+
+	//derived height is never after synced height
+	require.NoError(t, obe.UpdateDerivedHeight(4))
+	require.NoError(t, obe.UpdateSyncState("C1", 3))
+	state, err = obe.GetSyncState()
+	require.NoError(t, err)
+	require.Equal(t, &SyncState{ChainID: "C1", LastHeight: 3, DerivedHeight: 3}, state)
+}
@@ -0,0 +1,575 @@
+package database
+
+import (
+	"database/sql"
+	"math"
+	"sort"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/lib/pq"
+)
+
+//validatorsQuery selects validators with their proposal and signature stats, total power of active
+//validators is selected for computing voting power
+const validatorsQuery = `SELECT v.address, coalesce(v.pub_key, ''), v.power, v.first_height, v.last_change_height,
+	p.proposed, coalesce(p.last_proposed, 0), s.signed, s.missed,
+	(SELECT coalesce(SUM(power), 0) FROM validators) as total_power
+FROM validators v
+LEFT JOIN LATERAL
+(
+	SELECT COUNT(*) as proposed, MAX(height) as last_proposed
+	FROM blocks WHERE proposer_address=v.address
+) p ON true
+LEFT JOIN LATERAL
+(
+	SELECT COUNT(*) FILTER (WHERE signed) as signed, COUNT(*) FILTER (WHERE NOT signed) as missed
+	FROM validator_signatures WHERE address=v.address
+) s ON true`
+
+//HasGenesisValidators checks whether genesis validator set is saved
+func (obe *Postgre) HasGenesisValidators() (bool, error) {
+	sqlStatement := `SELECT EXISTS (SELECT 1 FROM validator_set_changes);`
+
+	var saved bool
+	err := obe.conn().QueryRow(sqlStatement).Scan(&saved)
+	return saved, err
+}
+
+//SaveGenesisValidators saves validators of genesis as set of first block. Later changes of set are
+//derived from bond and unbond txs, so it is skipped if genesis set is saved already
+func (obe *Postgre) SaveGenesisValidators(validators []hsBC.Validator) error {
+
+	sqlSaved := `SELECT EXISTS (SELECT 1 FROM validator_set_changes);`
+
+	sqlChange := `INSERT INTO validator_set_changes (height, address, old_power, new_power)
+	VALUES (1, $1, 0, $2);`
+
+	sqlUpsert := `INSERT INTO validators (address, pub_key, power, first_height, last_change_height)
+	VALUES ($1, $2, $3, 1, 1)
+	ON CONFLICT (address) DO UPDATE
+	SET pub_key = EXCLUDED.pub_key;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+
+		var saved bool
+		if err := conn.QueryRow(sqlSaved).Scan(&saved); err != nil {
+			return err
+		}
+		if saved {
+			return nil
+		}
+
+		for _, v := range validators {
+			if v.Power == 0 {
+				continue
+			}
+			if _, err := conn.Exec(sqlChange, v.Address, v.Power); err != nil {
+				return err
+			}
+			if _, err := conn.Exec(sqlUpsert, v.Address, v.PublicKey, v.Power); err != nil {
+				return err
+			}
+		}
+
+		return txAdapter.refreshValidators()
+	})
+}
+
+//SaveValidatorKeys updates public keys of saved validators, keys of validators
+//that are bonded after genesis are only known from validators of node
+func (obe *Postgre) SaveValidatorKeys(validators []hsBC.Validator) error {
+	sqlStatement := `UPDATE validators SET pub_key = $2
+	WHERE address = $1 AND pub_key IS DISTINCT FROM $2;`
+
+	for _, v := range validators {
+		if v.PublicKey == "" {
+			continue
+		}
+		if _, err := obe.conn().Exec(sqlStatement, v.Address, v.PublicKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//ReconcileValidators saves a reconciled set change at height for each validator whose power in set of node
+//is not same as its derived power, validators that are not in set of node anymore are set to zero power
+func (obe *Postgre) ReconcileValidators(validators []hsBC.Validator, height int64) error {
+	sqlChange := `INSERT INTO validator_set_changes (height, address, old_power, new_power, reconciled)
+	SELECT $1, $2, coalesce(v.power, 0), $3, true
+	FROM (SELECT $2::varchar as address) a
+	LEFT JOIN validators v ON v.address = a.address
+	WHERE coalesce(v.power, 0) <> $3;`
+
+	sqlRemoved := `INSERT INTO validator_set_changes (height, address, old_power, new_power, reconciled)
+	SELECT $1, address, power, 0, true FROM validators
+	WHERE power > 0 AND NOT (address = ANY($2));`
+
+	addresses := make([]string, 0, len(validators))
+	for _, v := range validators {
+		addresses = append(addresses, v.Address)
+	}
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		for _, v := range validators {
+			if _, err := conn.Exec(sqlChange, height, v.Address, v.Power); err != nil {
+				return err
+			}
+		}
+		if _, err := conn.Exec(sqlRemoved, height, pq.Array(addresses)); err != nil {
+			return err
+		}
+		return txAdapter.refreshValidators()
+	})
+}
+
+//refreshValidators sets power of validators to their latest known power and removes
+//validators that have no change of power anymore, e.g. after a rollback
+func (obe *Postgre) refreshValidators() error {
+	sqlUpsert := `INSERT INTO validators (address, power, first_height, last_change_height)
+	SELECT DISTINCT ON (address) address, power,
+		MIN(height) OVER (PARTITION BY address), MAX(height) OVER (PARTITION BY address)
+	FROM
+	(
+		` + validatorPowerRows + `
+	) c
+	ORDER BY address, height DESC, ord DESC, tx_id DESC
+	ON CONFLICT (address) DO UPDATE
+	SET power = EXCLUDED.power, first_height = EXCLUDED.first_height, last_change_height = EXCLUDED.last_change_height;`
+
+	sqlRemove := `DELETE FROM validators
+	WHERE address NOT IN (SELECT address FROM validator_set_changes UNION SELECT address FROM validator_power_changes);`
+
+	if _, err := obe.conn().Exec(sqlUpsert); err != nil {
+		return err
+	}
+	_, err := obe.conn().Exec(sqlRemove)
+	return err
+}
+
+//GetValidators returns all validators that have been in set, active ones first
+func (obe *Postgre) GetValidators() ([]Validator, error) {
+	sqlStatement := validatorsQuery + `
+	ORDER BY v.power DESC, v.address;`
+
+	rows, err := obe.conn().Query(sqlStatement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	validators := make([]Validator, 0)
+	for rows.Next() {
+		v, err := scanValidator(rows)
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, *v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return validators, nil
+}
+
+//GetValidator returns a validator details
+func (obe *Postgre) GetValidator(address string) (*Validator, error) {
+	sqlStatement := validatorsQuery + `
+	WHERE v.address=$1;`
+
+	row := obe.conn().QueryRow(sqlStatement, address)
+	return scanValidator(row)
+}
+
+//GetValidatorSetChanges returns latest changes of validator power by genesis and by bond and unbond txs,
+//latest change first. Changes of all validators are returned if address is empty
+func (obe *Postgre) GetValidatorSetChanges(address string, limit uint64) ([]ValidatorSetChange, error) {
+	sqlStatement := `SELECT height, address, old_power, new_power FROM
+	(
+		SELECT height, CASE WHEN reconciled THEN 2 ELSE 0 END as ord, 0 as tx_id, address, old_power, new_power FROM validator_set_changes
+		UNION ALL
+		SELECT height, 1 as ord, tx_id, address, GREATEST(power - delta, 0), power FROM validator_power_changes
+	) c
+	WHERE $1='' OR address=$1
+	ORDER BY height DESC, ord DESC, tx_id DESC
+	LIMIT $2;`
+
+	rows, err := obe.conn().Query(sqlStatement, address, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]ValidatorSetChange, 0)
+	for rows.Next() {
+		var c ValidatorSetChange
+		if err := rows.Scan(&c.Height, &c.Address, &c.OldPower, &c.NewPower); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+//InsertCommitSignatures saves signers of last commit of each block at previous height. Validators that
+//were in set at that height but did not sign are saved as missed. Set of a height is derived from genesis
+//and power changes of txs and reconciles, which are applied by consensus two blocks after their block. Misses are only
+//saved when derived set has as many validators as commit and contains all its signers. Blocks without
+//fetched commit are skipped
+func (obe *Postgre) InsertCommitSignatures(blocks []hsBC.BlockInfo) error {
+	heights := make([]int64, 0, len(blocks))
+	sizes := make([]int64, 0, len(blocks))
+	signerHeights := make([]int64, 0)
+	signers := make([]string, 0)
+	for _, b := range blocks {
+		if b.Height <= 1 || b.LastCommitSigners == nil {
+			continue
+		}
+
+		heights = append(heights, b.Height-1)
+		sizes = append(sizes, int64(b.LastCommitSize))
+		for _, address := range b.LastCommitSigners {
+			signerHeights = append(signerHeights, b.Height-1)
+			signers = append(signers, address)
+		}
+	}
+
+	if len(heights) == 0 {
+		return nil
+	}
+
+	sqlStatement := `WITH commits AS
+	(
+		SELECT height, size FROM unnest($1::bigint[], $2::bigint[]) as h(height, size)
+	),
+	signed AS
+	(
+		SELECT height, address FROM unnest($3::bigint[], $4::text[]) as s(height, address)
+	),
+	expected AS
+	(
+		SELECT h.height, vs.address FROM commits h
+		CROSS JOIN LATERAL
+		(
+			SELECT DISTINCT ON (c.address) c.address, c.power FROM
+			(
+				` + validatorPowerRows + `
+			) c
+			WHERE c.ord=0 OR c.height<=h.height-2
+			ORDER BY c.address, c.height DESC, c.ord DESC, c.tx_id DESC
+		) vs
+		WHERE vs.power>0
+	),
+	matched AS
+	(
+		SELECT h.height FROM commits h
+		WHERE h.size = (SELECT COUNT(*) FROM expected e WHERE e.height=h.height)
+		AND NOT EXISTS
+		(
+			SELECT 1 FROM signed s
+			WHERE s.height=h.height
+			AND NOT EXISTS (SELECT 1 FROM expected e WHERE e.height=s.height AND e.address=s.address)
+		)
+	)
+	INSERT INTO validator_signatures (height, address, signed)
+	SELECT height, address, bool_or(signed) FROM
+	(
+		SELECT height, address, false as signed FROM expected WHERE height IN (SELECT height FROM matched)
+		UNION ALL
+		SELECT height, address, true as signed FROM signed
+	) tblSignatures
+	GROUP BY height, address
+	ON CONFLICT (address, height) DO UPDATE
+	SET signed = EXCLUDED.signed;`
+
+	_, err := obe.conn().Exec(sqlStatement, pq.Array(heights), pq.Array(sizes), pq.Array(signerHeights), pq.Array(signers))
+	return err
+}
+
+//GetValidatorsLiveness returns signed and missed blocks of validators in last window heights
+//that have signatures, validators that missed more blocks come first
+func (obe *Postgre) GetValidatorsLiveness(window uint64) ([]ValidatorLiveness, error) {
+	sqlStatement := `SELECT address, COUNT(*) FILTER (WHERE signed), COUNT(*) FILTER (WHERE NOT signed),
+		coalesce(MAX(height) FILTER (WHERE signed), 0)
+	FROM validator_signatures
+	WHERE height > (SELECT coalesce(MAX(height), 0) FROM validator_signatures) - $1
+	GROUP BY address
+	ORDER BY 3 DESC, address;`
+
+	rows, err := obe.conn().Query(sqlStatement, window)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	liveness := make([]ValidatorLiveness, 0)
+	for rows.Next() {
+		var l ValidatorLiveness
+		if err := rows.Scan(&l.Address, &l.SignedBlocks, &l.MissedBlocks, &l.LastSignedHeight); err != nil {
+			return nil, err
+		}
+		liveness = append(liveness, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return liveness, nil
+}
+
+//GetValidatorSignatures returns signatures of validator in last window heights that have signatures, latest first
+func (obe *Postgre) GetValidatorSignatures(address string, window uint64) ([]ValidatorSignature, error) {
+	sqlStatement := `SELECT height, signed FROM validator_signatures
+	WHERE address=$1 AND height > (SELECT coalesce(MAX(height), 0) FROM validator_signatures) - $2
+	ORDER BY height DESC;`
+
+	rows, err := obe.conn().Query(sqlStatement, address, window)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	signatures := make([]ValidatorSignature, 0)
+	for rows.Next() {
+		var s ValidatorSignature
+		if err := rows.Scan(&s.Height, &s.Signed); err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return signatures, nil
+}
+
+//scanValidator reads a validator that is selected with validatorsQuery and computes its stats
+func scanValidator(row rowScanner) (*Validator, error) {
+	var v Validator
+	var totalPower uint64
+	err := row.Scan(&v.Address, &v.PublicKey, &v.Power, &v.FirstHeight, &v.LastChangeHeight,
+		&v.ProposedBlocks, &v.LastProposedHeight, &v.SignedBlocks, &v.MissedBlocks, &totalPower)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Active = v.Power > 0
+	if totalPower > 0 {
+		v.VotingPowerPercent = float64(v.Power) / float64(totalPower) * 100.0
+	}
+	v.Uptime = uptime(v.SignedBlocks, v.MissedBlocks)
+
+	return &v, nil
+}
+
+//uptime returns percentage of signed blocks to tracked blocks, it is nil when no signature of validator is tracked
+func uptime(signed uint64, missed uint64) *float64 {
+	tracked := signed + missed
+	if tracked == 0 {
+		return nil
+	}
+
+	percent := float64(signed) / float64(tracked) * 100.0
+	return &percent
+}
+
+//InsertValidatorPowerChanges saves power changes of saved bond and unbond transactions, saved changes are skipped
+func (obe *Postgre) InsertValidatorPowerChanges(changes []hsBC.ValidatorPowerChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	sqlStatement := `INSERT INTO validator_power_changes (tx_id, txhash, height, address, delta, power)
+	SELECT id, txhash, $2, $3, $4, $5 FROM transactions WHERE txhash=$1
+	ON CONFLICT (tx_id) DO NOTHING;`
+
+	return obe.runInTx(func(txAdapter *Postgre) error {
+		conn := txAdapter.conn()
+		for _, c := range changes {
+			_, err := conn.Exec(sqlStatement, c.TxHash, c.Height, c.Address, c.Delta, c.Power)
+			if err != nil {
+				return err
+			}
+		}
+		return txAdapter.refreshValidators()
+	})
+}
+
+//validatorPowerRows selects every known power of validators, set changes are genesis validators
+//and changes of bond and unbond txs come after them in order of ord and tx_id at same height.
+//Reconciled set changes come last, they hold power of validator in set of node after that height
+const validatorPowerRows = `SELECT height, CASE WHEN reconciled THEN 2 ELSE 0 END as ord, 0 as tx_id, address, new_power as power FROM validator_set_changes
+	UNION ALL
+	SELECT height, 1 as ord, tx_id, address, power FROM validator_power_changes`
+
+//validatorPowersQuery selects height, address and power of validatorPowerRows
+const validatorPowersQuery = `SELECT height, address, power FROM
+(
+	` + validatorPowerRows + `
+) c`
+
+//GetValidatorPower returns latest known power of validator before height
+func (obe *Postgre) GetValidatorPower(address string, beforeHeight int64) (uint64, error) {
+	sqlStatement := validatorPowersQuery + `
+	WHERE address=$1 AND height<$2
+	ORDER BY height DESC, ord DESC, tx_id DESC
+	LIMIT 1;`
+
+	var height int64
+	var addr string
+	var power uint64
+	err := obe.conn().QueryRow(sqlStatement, address, beforeHeight).Scan(&height, &addr, &power)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return power, err
+}
+
+//GetValidatorPowerChanges returns latest power changes of validator by bond and unbond txs, latest change first
+func (obe *Postgre) GetValidatorPowerChanges(address string, limit uint64) ([]hsBC.ValidatorPowerChange, error) {
+	sqlStatement := `SELECT height, txhash, address, delta, power FROM validator_power_changes
+	WHERE address=$1
+	ORDER BY height DESC, tx_id DESC
+	LIMIT $2;`
+
+	rows, err := obe.conn().Query(sqlStatement, address, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]hsBC.ValidatorPowerChange, 0)
+	for rows.Next() {
+		var c hsBC.ValidatorPowerChange
+		if err := rows.Scan(&c.Height, &c.TxHash, &c.Address, &c.Delta, &c.Power); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+//GetVotingPowerHistory returns voting power of validators at each height in range that it is changed,
+//starting after filter.AfterHeight. Powers before first height of page are replayed, so first point
+//has all validators. Zero to height means no limit
+func (obe *Postgre) GetVotingPowerHistory(filter VotingPowerFilter) ([]VotingPowerPoint, error) {
+	start := filter.FromHeight
+	if filter.AfterHeight >= start {
+		start = filter.AfterHeight + 1
+	}
+	toHeight := filter.ToHeight
+	if toHeight <= 0 {
+		toHeight = math.MaxInt64
+	}
+
+	//latest power of each validator before page is followed by all changes at heights of page
+	sqlStatement := `WITH c AS
+	(
+		` + validatorPowerRows + `
+	),
+	heights AS
+	(
+		SELECT DISTINCT height FROM c
+		WHERE height>=$1 AND height<=$2
+		ORDER BY height
+		LIMIT $3
+	)
+	SELECT height, address, power FROM
+	(
+		SELECT * FROM
+		(
+			SELECT DISTINCT ON (address) height, ord, tx_id, address, power FROM c
+			WHERE height<$1
+			ORDER BY address, height DESC, ord DESC, tx_id DESC
+		) prior
+		UNION ALL
+		SELECT height, ord, tx_id, address, power FROM c
+		WHERE height IN (SELECT height FROM heights)
+	) rows
+	ORDER BY height, ord, tx_id;`
+
+	rows, err := obe.conn().Query(sqlStatement, start, toHeight, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]powerRow, 0)
+	for rows.Next() {
+		var c powerRow
+		if err := rows.Scan(&c.height, &c.address, &c.power); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return votingPowerPoints(changes, start), nil
+}
+
+//powerRow is power of a validator after a change at height
+type powerRow struct {
+	height  int64
+	address string
+	power   uint64
+}
+
+//votingPowerPoints replays changes that are in order of height and returns a point for each height
+//from start, changes before start only make the powers that first point starts from
+func votingPowerPoints(changes []powerRow, start int64) []VotingPowerPoint {
+	points := make([]VotingPowerPoint, 0)
+	powers := make(map[string]uint64)
+	for i, c := range changes {
+		if c.power == 0 {
+			delete(powers, c.address)
+		} else {
+			powers[c.address] = c.power
+		}
+
+		last := i == len(changes)-1 || changes[i+1].height != c.height
+		if last && c.height >= start {
+			points = append(points, votingPowerPoint(c.height, powers))
+		}
+	}
+	return points
+}
+
+//votingPowerPoint makes a point of voting power history, validators with more power come first
+func votingPowerPoint(height int64, powers map[string]uint64) VotingPowerPoint {
+	point := VotingPowerPoint{Height: height, Validators: make([]ValidatorPower, 0, len(powers))}
+	for address, power := range powers {
+		point.TotalPower += power
+		point.Validators = append(point.Validators, ValidatorPower{Address: address, Power: power})
+	}
+
+	for i := range point.Validators {
+		point.Validators[i].SharePercent = float64(point.Validators[i].Power) * 100 / float64(point.TotalPower)
+	}
+	sort.Slice(point.Validators, func(i, j int) bool {
+		if point.Validators[i].Power != point.Validators[j].Power {
+			return point.Validators[i].Power > point.Validators[j].Power
+		}
+		return point.Validators[i].Address < point.Validators[j].Address
+	})
+	return point
+}
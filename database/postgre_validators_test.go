@@ -0,0 +1,145 @@
+package database
+
+import (
+	"testing"
+
+	hsBC "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVotingPowerPoints(t *testing.T) {
+	//changes before height 5 are latest powers that are loaded before page
+	changes := []powerRow{
+		{height: 2, address: "V1", power: 100},
+		{height: 3, address: "V3", power: 0},
+		{height: 5, address: "V2", power: 100},
+		{height: 5, address: "V2", power: 300},
+		{height: 7, address: "V1", power: 0},
+	}
+
+	points := votingPowerPoints(changes, 5)
+	require.Equal(t, []VotingPowerPoint{
+		{Height: 5, TotalPower: 400, Validators: []ValidatorPower{
+			{Address: "V2", Power: 300, SharePercent: 75},
+			{Address: "V1", Power: 100, SharePercent: 25},
+		}},
+		{Height: 7, TotalPower: 300, Validators: []ValidatorPower{
+			{Address: "V2", Power: 300, SharePercent: 100},
+		}},
+	}, points)
+
+	require.Empty(t, votingPowerPoints(changes[:2], 5))
+	require.Empty(t, votingPowerPoints(nil, 0))
+}
+
+func TestUptime(t *testing.T) {
+	require.Nil(t, uptime(0, 0))
+	require.Equal(t, 100.0, *uptime(10, 0))
+	require.Equal(t, 75.0, *uptime(3, 1))
+	require.Equal(t, 0.0, *uptime(0, 5))
+}
+
+func TestReconcileValidators(t *testing.T) {
+	obe := connectTestDB(t, "reconcile_validators_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	require.NoError(t, obe.SaveGenesisValidators([]hsBC.Validator{{Address: "V1", Power: 10}, {Address: "V2", Power: 20}}))
+	insertTestTxs(t, obe, []hsBC.Transaction{{Type: "BondTx", BlockID: 4, Hash: "T4", From: "V1"}})
+	require.NoError(t, obe.InsertValidatorPowerChanges([]hsBC.ValidatorPowerChange{
+		{TxHash: "T4", Height: 4, Address: "V1", Delta: 5, Power: 15},
+	}))
+
+	powers := func() map[string]uint64 {
+		validators, err := obe.GetValidators()
+		require.NoError(t, err)
+		p := make(map[string]uint64)
+		for _, v := range validators {
+			p[v.Address] = v.Power
+		}
+		return p
+	}
+
+	//V1 is already at its power, V2 is not in set of node and V3 is bonded without a seen tx
+	require.NoError(t, obe.ReconcileValidators([]hsBC.Validator{{Address: "V1", Power: 15}, {Address: "V3", Power: 7}}, 6))
+	require.Equal(t, map[string]uint64{"V1": 15, "V2": 0, "V3": 7}, powers())
+
+	changes, err := obe.GetValidatorSetChanges("", 10)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []ValidatorSetChange{
+		{Height: 6, Address: "V2", OldPower: 20, NewPower: 0},
+		{Height: 6, Address: "V3", OldPower: 0, NewPower: 7},
+		{Height: 4, Address: "V1", OldPower: 10, NewPower: 15},
+		{Height: 1, Address: "V1", OldPower: 0, NewPower: 10},
+		{Height: 1, Address: "V2", OldPower: 0, NewPower: 20},
+	}, changes)
+
+	//reconciling same set again saves nothing
+	require.NoError(t, obe.ReconcileValidators([]hsBC.Validator{{Address: "V1", Power: 15}, {Address: "V3", Power: 7}}, 6))
+	changes, err = obe.GetValidatorSetChanges("", 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 5)
+
+	//reconciled power is power of validator before next bond
+	power, err := obe.GetValidatorPower("V3", 7)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), power)
+
+	//reconciled changes are removed with their blocks
+	require.NoError(t, obe.RollbackBlocks(5))
+	require.Equal(t, map[string]uint64{"V1": 15, "V2": 20}, powers())
+}
+
+func TestInsertCommitSignatures(t *testing.T) {
+	obe := connectTestDB(t, "commit_signatures_test")
+	defer obe.Disconnect()
+	require.NoError(t, obe.Migrate())
+
+	require.NoError(t, obe.SaveGenesisValidators([]hsBC.Validator{{Address: "V1", Power: 10}, {Address: "V2", Power: 20}}))
+	//V3 is bonded at height 4, so it is in set from height 6
+	insertTestTxs(t, obe, []hsBC.Transaction{{Type: "BondTx", BlockID: 4, Hash: "T4", From: "V3"}})
+	require.NoError(t, obe.InsertValidatorPowerChanges([]hsBC.ValidatorPowerChange{
+		{TxHash: "T4", Height: 4, Address: "V3", Delta: 30, Power: 30},
+	}))
+
+	commit := func(height int64, size int, signers ...string) hsBC.BlockInfo {
+		return hsBC.BlockInfo{Height: height, LastCommitSize: size, LastCommitSigners: signers}
+	}
+	require.NoError(t, obe.InsertCommitSignatures([]hsBC.BlockInfo{
+		//first block has no last commit
+		commit(1, 1, "V1"),
+		commit(2, 2, "V1", "V2"),
+		commit(3, 2, "V1"),
+		//V9 is not in derived set, so misses of height 3 are not known
+		commit(4, 2, "V1", "V9"),
+		//commit of block 5 is not fetched
+		{Height: 5},
+		//V3 is not in set of height 5 yet, so size of commit does not match
+		commit(6, 3, "V1"),
+		commit(7, 3, "V1", "V2"),
+	}))
+
+	liveness, err := obe.GetValidatorsLiveness(100)
+	require.NoError(t, err)
+	require.Equal(t, []ValidatorLiveness{
+		{Address: "V2", SignedBlocks: 2, MissedBlocks: 1, LastSignedHeight: 6},
+		{Address: "V3", SignedBlocks: 0, MissedBlocks: 1, LastSignedHeight: 0},
+		{Address: "V1", SignedBlocks: 5, MissedBlocks: 0, LastSignedHeight: 6},
+		{Address: "V9", SignedBlocks: 1, MissedBlocks: 0, LastSignedHeight: 3},
+	}, liveness)
+
+	//only heights 5 and 6 are in window
+	liveness, err = obe.GetValidatorsLiveness(2)
+	require.NoError(t, err)
+	require.Equal(t, []ValidatorLiveness{
+		{Address: "V3", SignedBlocks: 0, MissedBlocks: 1, LastSignedHeight: 0},
+		{Address: "V1", SignedBlocks: 2, MissedBlocks: 0, LastSignedHeight: 6},
+		{Address: "V2", SignedBlocks: 1, MissedBlocks: 0, LastSignedHeight: 6},
+	}, liveness)
+
+	//a later commit of same height replaces a miss
+	require.NoError(t, obe.InsertCommitSignatures([]hsBC.BlockInfo{commit(3, 2, "V1", "V2")}))
+	signatures, err := obe.GetValidatorSignatures("V2", 100)
+	require.NoError(t, err)
+	require.Equal(t, []ValidatorSignature{{Height: 6, Signed: true}, {Height: 2, Signed: true}, {Height: 1, Signed: true}}, signatures)
+}
@@ -0,0 +1,50 @@
+package explorer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurrowBlocks/abi"
+)
+
+//LoadABIs saves ABIs of abi dir that is set in config. Each file is ABI json of a contract
+//that is named by contract address, like 1A2B...3C.json
+func (e *Explorer) LoadABIs() error {
+	dir := e.Config.App.ABIDir
+	if dir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	loaded := 0
+	for _, file := range files {
+		address := strings.TrimSuffix(filepath.Base(file), ".json")
+		if !abi.IsAddress(address) {
+			println("abi file " + file + " is not named by a contract address, it is skipped")
+			continue
+		}
+		address = abi.NormalizeAddress(address)
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("error on reading abi file %s: %w", file, err)
+		}
+		if _, err := abi.Parse(data); err != nil {
+			return fmt.Errorf("error on parsing abi file %s: %w", file, err)
+		}
+
+		if err := e.DBAdapter.SaveABI(address, string(data)); err != nil {
+			return fmt.Errorf("error on saving abi of %s in db: %w", address, err)
+		}
+		loaded++
+	}
+
+	println(loaded, "contract abis loaded from", dir)
+	return nil
+}
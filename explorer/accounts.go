@@ -0,0 +1,118 @@
+package explorer
+
+import (
+	"fmt"
+	"sync"
+
+	bc "github.com/BurrowBlocks/blockchain"
+)
+
+//loadAccounts saves all accounts of blockchain when accounts table is empty and returns them,
+//accounts that are created in genesis are not touched by any transaction. It returns nil
+//if accounts are loaded before
+func (e *Explorer) loadAccounts() ([]*bc.Account, error) {
+	lastID, err := e.DBAdapter.GetAccountsTableLastID()
+	if err != nil {
+		return nil, fmt.Errorf("error on reading last account id from db: %w", err)
+	}
+	if lastID > 0 {
+		return nil, nil
+	}
+
+	accs, err := e.BCAdapter.GetAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("error on reading accounts: %w", err)
+	}
+
+	err = e.DBAdapter.SaveAccounts(accs)
+	if err != nil {
+		return nil, fmt.Errorf("error on saving accounts in db: %w", err)
+	}
+	return accs, nil
+}
+
+//addTouchedAddresses adds addresses of all participants of txs of page and contracts
+//that are deployed by them to touched
+func addTouchedAddresses(page *blockPage, touched map[string]bool) {
+	add := func(address string) {
+		if address != "" {
+			touched[address] = true
+		}
+	}
+
+	for _, txs := range page.txs {
+		for i := range txs {
+			for _, address := range txs[i].Addresses() {
+				add(address)
+			}
+		}
+	}
+	for _, contracts := range page.contracts {
+		for _, contract := range contracts {
+			add(contract.Address)
+		}
+	}
+}
+
+//refreshAccounts saves latest state of touched accounts and returns them. It runs once after
+//syncing, so each account is read from node once however many blocks touched it. Blocks are
+//already saved, so a failure is only logged and accounts are refreshed again next time they are touched
+func (e *Explorer) refreshAccounts(touched map[string]bool) []*bc.Account {
+	if len(touched) == 0 {
+		return nil
+	}
+
+	workers := e.Config.App.FetchWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	accs := make([]*bc.Account, 0, len(touched))
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, workers)
+	for address := range touched {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(address string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			acc, err := e.BCAdapter.GetAccount(address)
+			if err != nil {
+				println("error on reading account " + address + ": " + err.Error())
+				return
+			}
+
+			mtx.Lock()
+			accs = append(accs, acc)
+			mtx.Unlock()
+		}(address)
+	}
+	wg.Wait()
+
+	err := e.DBAdapter.SaveAccounts(accs)
+	if err != nil {
+		println("error on saving accounts in db: " + err.Error())
+		return nil
+	}
+	return accs
+}
+
+//reconcileBalances fixes balance ledger of accounts by their balances that node reports.
+//Node reports latest balances, so it is only done when height is still last block of node
+func (e *Explorer) reconcileBalances(accs []*bc.Account, height uint64) {
+	if len(accs) == 0 {
+		return
+	}
+
+	lastHeight, err := e.BCAdapter.GetBlocksLastHeight()
+	if err != nil || lastHeight != height {
+		return
+	}
+
+	err = e.DBAdapter.ReconcileBalances(accs, int64(height))
+	if err != nil {
+		println("error on reconciling balances in db: " + err.Error())
+	}
+}
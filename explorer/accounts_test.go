@@ -0,0 +1,82 @@
+package explorer
+
+import (
+	"errors"
+	"testing"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	"github.com/stretchr/testify/require"
+)
+
+//failingAccountChain fails reading account fail from node
+type failingAccountChain struct {
+	*memChain
+	fail string
+}
+
+func (c *failingAccountChain) GetAccount(address string) (*bc.Account, error) {
+	if address == c.fail {
+		return nil, errors.New("account is not available")
+	}
+	return c.memChain.GetAccount(address)
+}
+
+func TestLoadAccounts(t *testing.T) {
+	accs := []*bc.Account{{Address: "A1", Balance: 50}, {Address: "G1", Balance: 100}}
+	chain := &memChain{height: 3, accounts: accs}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+
+	loaded, err := e.loadAccounts()
+	require.NoError(t, err)
+	require.Equal(t, accs, loaded)
+	require.Equal(t, bc.Account{Address: "G1", Balance: 100}, dbAdapter.data.accounts["G1"])
+
+	//accounts are only loaded once
+	chain.accounts = []*bc.Account{{Address: "A1", Balance: 70}}
+	loaded, err = e.loadAccounts()
+	require.NoError(t, err)
+	require.Nil(t, loaded)
+	require.Equal(t, uint64(50), dbAdapter.data.accounts["A1"].Balance)
+}
+
+func TestRefreshAccounts(t *testing.T) {
+	chain := &failingAccountChain{memChain: &memChain{height: 3}, fail: "B1"}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain.memChain, dbAdapter)
+	e.BCAdapter = chain
+
+	require.Nil(t, e.refreshAccounts(nil))
+
+	//account that can't be read is refreshed next time it is touched
+	accs := e.refreshAccounts(map[string]bool{"A1": true, "B1": true, "C1": true})
+	require.ElementsMatch(t, []*bc.Account{{Address: "A1", Balance: 1}, {Address: "C1", Balance: 1}}, accs)
+	require.Len(t, dbAdapter.data.accounts, 2)
+	require.NotContains(t, dbAdapter.data.accounts, "B1")
+}
+
+func TestUpdateAllKeepsGenesisAccounts(t *testing.T) {
+	//G1 is only in genesis, so no tx touches it
+	chain := &memChain{height: 3, accounts: []*bc.Account{{Address: "A1", Balance: 50}, {Address: "G1", Balance: 100}}}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+
+	//loaded accounts are latest, they are not replaced by refreshing touched accounts
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, map[string]bc.Account{
+		"A1": {Address: "A1", Balance: 50},
+		"G1": {Address: "G1", Balance: 100},
+	}, dbAdapter.data.accounts)
+	require.Equal(t, int64(3), dbAdapter.reconciledAt)
+
+	//later updates only refresh accounts that are touched by new blocks
+	chain.height = 4
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, map[string]bc.Account{
+		"A1": {Address: "A1", Balance: 50},
+		"G1": {Address: "G1", Balance: 100},
+		"A4": {Address: "A4", Balance: 1},
+		"B4": {Address: "B4", Balance: 1},
+	}, dbAdapter.data.accounts)
+	require.Equal(t, int64(4), dbAdapter.reconciledAt)
+}
@@ -0,0 +1,78 @@
+package explorer
+
+import (
+	"fmt"
+	"sort"
+
+	db "github.com/BurrowBlocks/database"
+)
+
+//Backfill finds missing blocks and blocks with missing transactions up to
+//sync checkpoint and fetches them again from blockchain. Only blocks and txs are saved,
+//derived state from first backfilled height is rolled back with them, so next update
+//derives it again in height order and an older value never overwrites a later one
+func (e *Explorer) Backfill() error {
+	lastHeight, _, err := e.getSyncedHeight()
+	if err != nil {
+		return fmt.Errorf("error on reading last block id from db: %w", err)
+	}
+
+	gaps, err := e.DBAdapter.GetMissingBlockRanges(lastHeight)
+	if err != nil {
+		return fmt.Errorf("error on finding missing blocks: %w", err)
+	}
+
+	heights, err := e.DBAdapter.GetBlocksWithMissingTxs(lastHeight)
+	if err != nil {
+		return fmt.Errorf("error on finding blocks with missing txs: %w", err)
+	}
+
+	ranges := append(gaps, heightsToRanges(heights)...)
+	if len(ranges) == 0 {
+		println("\nno missing blocks found up to block", lastHeight)
+		return nil
+	}
+
+	//executions are fetched when backfilled heights are derived
+	pipeline := newFetchPipeline(e.BCAdapter, e.Config.App, blocksPageSize)
+	pipeline.indexExecutions = false
+	for _, r := range ranges {
+		println("\nbackfilling blocks", r.From, "to", r.To, "...")
+
+		errBackfill := pipeline.run(r.From, r.To, func(page *blockPage) error {
+			return e.runInDBTx(func(dbTx db.TxAdapter) error {
+				err := e.saveBlocksInDB(page, dbTx)
+				if err != nil {
+					return err
+				}
+				return dbTx.RollbackDerivedState(int64(page.from))
+			})
+		})
+		if errBackfill != nil {
+			return fmt.Errorf("error on backfilling blocks %d to %d: %w", r.From, r.To, errBackfill)
+		}
+	}
+
+	println(len(ranges), "ranges of blocks backfilled, their state is derived on next update!")
+	return nil
+}
+
+//heightsToRanges merges heights into sorted ranges of consecutive heights
+func heightsToRanges(heights []uint64) []db.HeightRange {
+	sorted := append([]uint64(nil), heights...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	ranges := make([]db.HeightRange, 0)
+	for _, h := range sorted {
+		l := len(ranges)
+		if l > 0 && ranges[l-1].To >= h {
+			continue
+		}
+		if l > 0 && ranges[l-1].To+1 == h {
+			ranges[l-1].To = h
+			continue
+		}
+		ranges = append(ranges, db.HeightRange{From: h, To: h})
+	}
+	return ranges
+}
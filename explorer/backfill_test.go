@@ -0,0 +1,70 @@
+package explorer
+
+import (
+	"testing"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	db "github.com/BurrowBlocks/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeightsToRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		heights []uint64
+		ranges  []db.HeightRange
+	}{
+		{"empty", nil, []db.HeightRange{}},
+		{"single", []uint64{7}, []db.HeightRange{{From: 7, To: 7}}},
+		{"contiguous", []uint64{3, 4, 5, 6}, []db.HeightRange{{From: 3, To: 6}}},
+		{"gapped", []uint64{1, 2, 5, 9, 10}, []db.HeightRange{{From: 1, To: 2}, {From: 5, To: 5}, {From: 9, To: 10}}},
+		{"unsorted", []uint64{10, 2, 9, 1, 5}, []db.HeightRange{{From: 1, To: 2}, {From: 5, To: 5}, {From: 9, To: 10}}},
+		{"duplicated", []uint64{4, 3, 4, 3}, []db.HeightRange{{From: 3, To: 4}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.ranges, heightsToRanges(test.heights))
+		})
+	}
+}
+
+func TestHeightsToRangesKeepsInput(t *testing.T) {
+	heights := []uint64{3, 1, 2}
+	heightsToRanges(heights)
+	require.Equal(t, []uint64{3, 1, 2}, heights)
+}
+
+func TestBackfillDerivesStateInHeightOrder(t *testing.T) {
+	nameTx := func(height int64, data string) bc.Transaction {
+		return bc.Transaction{Type: "NameTx", BlockID: height, Hash: "N" + data, From: "A1", Amount: 10000, Fee: 10,
+			Name: "alice", Data: data, Inputs: []bc.TxIO{{Address: "A1", Amount: 10000}}}
+	}
+	chain := &memChain{height: 10, txs: map[int64][]bc.Transaction{5: {nameTx(5, "old")}, 8: {nameTx(8, "new")}}}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+
+	//name is updated at height 8 while block 5 is missed
+	require.NoError(t, e.UpdateAll())
+	delete(dbAdapter.data.blocks, 5)
+	delete(dbAdapter.data.txs, "Nold")
+	dbAdapter.data.names = dbAdapter.data.names[1:]
+	dbAdapter.data.executions = append(dbAdapter.data.executions[:4], dbAdapter.data.executions[5:]...)
+
+	//derived state from backfilled block is rolled back with it
+	require.NoError(t, e.Backfill())
+	require.Contains(t, dbAdapter.data.blocks, int64(5))
+	require.Contains(t, dbAdapter.data.txs, "Nold")
+	require.Len(t, dbAdapter.data.executions, 4)
+	require.Empty(t, dbAdapter.data.names)
+	require.Equal(t, &db.SyncState{ChainID: "C1", LastHeight: 10, DerivedHeight: 4}, dbAdapter.data.state)
+
+	//next update derives it again in height order, so older name does not overwrite latest one
+	require.NoError(t, e.UpdateAll())
+	require.Len(t, dbAdapter.data.executions, 10)
+	require.Equal(t, []string{"old", "new"}, []string{dbAdapter.data.names[0].Data, dbAdapter.data.names[1].Data})
+	name, err := dbAdapter.GetName("alice")
+	require.NoError(t, err)
+	require.Equal(t, "new", name.Data)
+	require.Equal(t, uint64(10), dbAdapter.data.state.DerivedHeight)
+}
@@ -0,0 +1,26 @@
+package explorer
+
+import (
+	bc "github.com/BurrowBlocks/blockchain"
+	db "github.com/BurrowBlocks/database"
+)
+
+//saveValidatorPowerChangesInDB saves changes of voting power that are made by BondTxs and UnbondTxs of page
+//in height order, power of each change is counted from genesis set and saved changes before it.
+//Txs whose execution failed or is unknown are skipped
+func (e *Explorer) saveValidatorPowerChangesInDB(page *blockPage, dbAdapter db.Adapter) error {
+	txs := make([]bc.Transaction, 0)
+	succeeded := make(map[string]bool)
+	for _, block := range page.blocks {
+		for hash := range succeededTxHashes(page.executions[block.Height]) {
+			succeeded[hash] = true
+		}
+		txs = append(txs, page.txs[block.Height]...)
+	}
+
+	changes, err := bc.PowerChanges(txs, succeeded, dbAdapter.GetValidatorPower)
+	if err != nil {
+		return err
+	}
+	return dbAdapter.InsertValidatorPowerChanges(changes)
+}
@@ -0,0 +1,122 @@
+package explorer
+
+import (
+	"fmt"
+	"strings"
+
+	db "github.com/BurrowBlocks/database"
+)
+
+//deriveMissingState derives state of heights after derived height up to synced height, they are synced
+//without executions or backfilled. State is derived in height order and only up to first block that is
+//not fully saved. It returns height that state is derived up to
+func (e *Explorer) deriveMissingState(syncedHeight uint64) (uint64, error) {
+	state, err := e.DBAdapter.GetSyncState()
+	if err != nil {
+		return 0, fmt.Errorf("error on reading sync state from db: %w", err)
+	}
+	//derived height is saved with sync checkpoint, it is derived after first page creates it
+	if state == nil {
+		return 0, nil
+	}
+	derivedHeight := state.DerivedHeight
+	if !e.Config.App.IndexExecutions || derivedHeight >= syncedHeight {
+		return derivedHeight, nil
+	}
+
+	toHeight, err := e.lastFullySavedHeight(derivedHeight, syncedHeight)
+	if err != nil {
+		return derivedHeight, err
+	}
+	if toHeight < syncedHeight {
+		println("\nerror: block", toHeight+1, "is not fully saved, state after block", toHeight, "is derived after it is backfilled")
+	}
+	if toHeight <= derivedHeight {
+		return derivedHeight, nil
+	}
+
+	pipeline, err := e.newPipeline()
+	if err != nil {
+		return derivedHeight, err
+	}
+
+	//databases that are synced before derived height was saved have state after it
+	from := derivedHeight + 1
+	err = e.DBAdapter.RollbackDerivedState(int64(from))
+	if err != nil {
+		return derivedHeight, fmt.Errorf("error on rolling back derived state from %d: %w", from, err)
+	}
+
+	println("\nderiving state of blocks", from, "to", toHeight, "...")
+	err = pipeline.run(from, toHeight, func(page *blockPage) error {
+		forkErr := e.checkSavedPage(page)
+		if forkErr != nil {
+			return forkErr
+		}
+
+		deriveErr := e.runInDBTx(func(dbTx db.TxAdapter) error {
+			err := e.saveDerivedStateInDB(page, dbTx)
+			if err != nil {
+				return err
+			}
+			return dbTx.UpdateDerivedHeight(int64(page.to))
+		})
+		if deriveErr != nil {
+			return fmt.Errorf("error on deriving state of blocks %d to %d in db: %w", page.from, page.to, deriveErr)
+		}
+		derivedHeight = page.to
+		return nil
+	})
+	return derivedHeight, err
+}
+
+//lastFullySavedHeight returns last height up to toHeight that blocks after fromHeight and their txs are saved up to
+func (e *Explorer) lastFullySavedHeight(fromHeight uint64, toHeight uint64) (uint64, error) {
+	gaps, err := e.DBAdapter.GetMissingBlockRanges(toHeight)
+	if err != nil {
+		return 0, fmt.Errorf("error on finding missing blocks: %w", err)
+	}
+
+	heights, err := e.DBAdapter.GetBlocksWithMissingTxs(toHeight)
+	if err != nil {
+		return 0, fmt.Errorf("error on finding blocks with missing txs: %w", err)
+	}
+
+	last := toHeight
+	for _, r := range append(gaps, heightsToRanges(heights)...) {
+		if r.To <= fromHeight {
+			continue
+		}
+		if r.From <= fromHeight {
+			return fromHeight, nil
+		}
+		if r.From-1 < last {
+			last = r.From - 1
+		}
+	}
+	return last, nil
+}
+
+//checkSavedPage checks that last block of page is same as saved block, so state is only derived from
+//txs of saved blocks. Saved blocks after common ancestor are rolled back if chain is forked
+func (e *Explorer) checkSavedPage(page *blockPage) error {
+	last := page.blocks[len(page.blocks)-1]
+	savedHash, err := e.DBAdapter.GetBlockHash(last.Height)
+	if err != nil {
+		return fmt.Errorf("error on reading hash of block %d from db: %w", last.Height, err)
+	}
+	if strings.EqualFold(savedHash, last.BlockHash) {
+		return nil
+	}
+
+	ancestor, err := e.findCommonAncestor(last.Height - 1)
+	if err != nil {
+		return err
+	}
+	err = e.rollbackTo(ancestor, last.ChainID)
+	if err != nil {
+		return err
+	}
+	println("\nchain is forked, blocks after", ancestor, "are rolled back")
+	return errRolledBack
+}
@@ -0,0 +1,172 @@
+package explorer
+
+import (
+	"testing"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	db "github.com/BurrowBlocks/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateAllDerivesGapWhenExecutionsAreEnabled(t *testing.T) {
+	chain := &memChain{height: 3}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+	e.Config.App.IndexExecutions = false
+
+	//blocks are synced without derived state, so ledger and validators are not reconciled
+	require.NoError(t, e.UpdateAll())
+	require.Len(t, dbAdapter.data.blocks, 3)
+	require.Empty(t, dbAdapter.data.executions)
+	require.Equal(t, 0, dbAdapter.data.changes)
+	require.Equal(t, &db.SyncState{ChainID: "C1", LastHeight: 3}, dbAdapter.data.state)
+	require.Equal(t, int64(-1), dbAdapter.reconciledAt)
+	require.Equal(t, int64(-1), dbAdapter.validatorsReconciledAt)
+
+	//gap is derived in height order before new blocks
+	chain.height = 4
+	e.Config.App.IndexExecutions = true
+	require.NoError(t, e.UpdateAll())
+	heights := make([]int64, 0)
+	for _, exec := range dbAdapter.data.executions {
+		heights = append(heights, exec.Height)
+	}
+	require.Equal(t, []int64{1, 2, 3, 4}, heights)
+	require.Equal(t, &db.SyncState{ChainID: "C1", LastHeight: 4, DerivedHeight: 4}, dbAdapter.data.state)
+	require.Equal(t, int64(4), dbAdapter.reconciledAt)
+}
+
+func TestDeriveMissingStateStopsAtMissingBlock(t *testing.T) {
+	chain := &memChain{height: 7}
+	dbAdapter := newMemDB()
+	dbAdapter.saveBlocks(chain, 6)
+	dbAdapter.data.state.DerivedHeight = 1
+	delete(dbAdapter.data.blocks, 4)
+	e := newMemExplorer(chain, dbAdapter)
+
+	//new blocks are still synced, state after missing block is derived once it is backfilled
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, &db.SyncState{ChainID: "C1", LastHeight: 7, DerivedHeight: 3}, dbAdapter.data.state)
+	require.Len(t, dbAdapter.data.executions, 2)
+	require.Equal(t, int64(-1), dbAdapter.reconciledAt)
+}
+
+func TestDeriveMissingStateRollsBackFork(t *testing.T) {
+	chain := &memChain{height: 5}
+	dbAdapter := newMemDB()
+	dbAdapter.saveBlocks(chain, 5)
+	dbAdapter.data.state.DerivedHeight = 0
+	//block 5 is saved from a chain that is forked away
+	forked := dbAdapter.data.blocks[5]
+	forked.BlockHash = "X5"
+	dbAdapter.data.blocks[5] = forked
+	e := newMemExplorer(chain, dbAdapter)
+
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, int64(5), dbAdapter.rolledBackFrom)
+	require.Equal(t, &db.SyncState{ChainID: "C1", LastHeight: 4}, dbAdapter.data.state)
+	require.Empty(t, dbAdapter.data.executions)
+
+	//saved blocks are derived and forked block is saved again
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, &db.SyncState{ChainID: "C1", LastHeight: 5, DerivedHeight: 5}, dbAdapter.data.state)
+	require.Len(t, dbAdapter.data.executions, 5)
+}
+
+func TestUpdateAllDerivesLogsOfGap(t *testing.T) {
+	chain := &memChain{height: 2, logs: []bc.TxEvent{{EventType: "Log", Address: "C1", Topics: []string{"AB"}}}}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+	e.Config.App.IndexExecutions = false
+
+	require.NoError(t, e.UpdateAll())
+	require.Empty(t, dbAdapter.data.executions)
+
+	//logs are saved with executions of gap
+	e.Config.App.IndexExecutions = true
+	require.NoError(t, e.UpdateAll())
+	require.Len(t, dbAdapter.data.executions, 2)
+	for _, exec := range dbAdapter.data.executions {
+		require.Equal(t, chain.logs, exec.Events)
+	}
+}
+
+func TestUpdateAllDerivesContractsOfGap(t *testing.T) {
+	deploy := bc.Transaction{Type: "CallTx", BlockID: 2, Hash: "D2", From: "A1", Data: "6080"}
+	chain := &memChain{height: 3, txs: map[int64][]bc.Transaction{2: {deploy}}}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+	e.Config.App.IndexExecutions = false
+
+	//address of a deployed contract is only known from execution of its tx
+	require.NoError(t, e.UpdateAll())
+	require.Empty(t, dbAdapter.data.contracts)
+
+	e.Config.App.IndexExecutions = true
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, []bc.Contract{{Address: "CD2", Creator: "A1", TxHash: "D2", Height: 2, CodeHash: bc.CodeHash("")}},
+		dbAdapter.data.contracts)
+}
+
+func TestUpdateAllDerivesNamesOfGap(t *testing.T) {
+	nameTx := bc.Transaction{Type: "NameTx", BlockID: 2, Hash: "N2", From: "A1", Amount: 10000, Fee: 10,
+		Name: "alice", Data: "gap", Inputs: []bc.TxIO{{Address: "A1", Amount: 10000}}}
+	chain := &memChain{height: 3, txs: map[int64][]bc.Transaction{2: {nameTx}}}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+	e.Config.App.IndexExecutions = false
+
+	//a NameTx that may have failed does not register its name
+	require.NoError(t, e.UpdateAll())
+	name, err := dbAdapter.GetName("alice")
+	require.NoError(t, err)
+	require.Nil(t, name)
+
+	e.Config.App.IndexExecutions = true
+	require.NoError(t, e.UpdateAll())
+	name, err = dbAdapter.GetName("alice")
+	require.NoError(t, err)
+	require.Equal(t, "gap", name.Data)
+	require.Equal(t, int64(2), name.Height)
+}
+
+func TestUpdateAllDerivesPermissionChangesOfGap(t *testing.T) {
+	permsTx := bc.Transaction{Type: "PermsTx", BlockID: 2, Hash: "P2", From: "A1",
+		Data: `{"Action":4096,"Target":"B1","Permission":2,"Value":true}`}
+	chain := &memChain{height: 3, txs: map[int64][]bc.Transaction{2: {permsTx}}}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+	e.Config.App.IndexExecutions = false
+
+	require.NoError(t, e.UpdateAll())
+	require.Empty(t, dbAdapter.data.perms)
+
+	e.Config.App.IndexExecutions = true
+	require.NoError(t, e.UpdateAll())
+	require.Len(t, dbAdapter.data.perms, 1)
+	require.Equal(t, "B1", dbAdapter.data.perms[0].Address)
+	require.Equal(t, []string{"send"}, dbAdapter.data.perms[0].Permissions)
+}
+
+func TestUpdateAllDerivesValidatorPowerOfGap(t *testing.T) {
+	bondTx := bc.Transaction{Type: "BondTx", BlockID: 2, Hash: "B2", From: "V1", Amount: 5}
+	validators := []bc.Validator{{Address: "V1", Power: 5}}
+	chain := &memChain{height: 3, txs: map[int64][]bc.Transaction{2: {bondTx}},
+		validators: &bc.ValidatorSet{Height: 3, Validators: validators}}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+	e.Config.App.IndexExecutions = false
+
+	//set is not reconciled over a gap, that would count the bond again once it is derived
+	require.NoError(t, e.UpdateAll())
+	require.Empty(t, dbAdapter.data.powers)
+	require.Equal(t, int64(-1), dbAdapter.validatorsReconciledAt)
+
+	chain.height = 4
+	chain.validators.Height = 4
+	e.Config.App.IndexExecutions = true
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, []bc.ValidatorPowerChange{{Height: 2, TxHash: "B2", Address: "V1", Delta: 5, Power: 5}},
+		dbAdapter.data.powers)
+	require.Equal(t, int64(4), dbAdapter.validatorsReconciledAt)
+}
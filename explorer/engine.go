@@ -1,7 +1,10 @@
 package explorer
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	bc "github.com/BurrowBlocks/blockchain"
 	config "github.com/BurrowBlocks/config"
@@ -10,11 +13,20 @@ import (
 
 var numDots int
 
+//blocksPageSize is number of blocks that are fetched and saved together
+const blocksPageSize = 1000
+
+//errRolledBack stops syncing when saved blocks are rolled back after a fork
+var errRolledBack = errors.New("saved blocks are rolled back")
+
 //Explorer class for connecting block chain to data base
 type Explorer struct {
 	BCAdapter bc.Adapter
 	DBAdapter db.Adapter
 	Config    *config.Config
+
+	//lastRichListRefresh is when ranks of rich list are recomputed last time
+	lastRichListRefresh time.Time
 }
 
 //Init to initialize database and block chain
@@ -29,6 +41,16 @@ func (e *Explorer) Init() error {
 	}
 	bcAdapter.Update()
 
+	//node that does not serve executions can not be synced while they are indexed, other errors are
+	//only logged since node may be down for now
+	execErr := e.checkTxExecutions()
+	if errors.Is(execErr, errNoTxExecutions) {
+		return execErr
+	}
+	if execErr != nil {
+		println("error: " + execErr.Error())
+	}
+
 	//connect to database
 	dbAdapter := e.DBAdapter
 	connErr := dbAdapter.Connect()
@@ -58,15 +80,32 @@ func (e *Explorer) UpdateAll() error {
 	//Get current height of last block
 	currentHeight, getLastHeightErr := e.BCAdapter.GetBlocksLastHeight()
 	if getLastHeightErr != nil {
-		println("error on reading last block id: " + getLastHeightErr.Error())
-		return getLastHeightErr
+		return fmt.Errorf("error on reading last block id: %w", getLastHeightErr)
 	}
 
-	//Get last block ID that is saved
-	lastBlockIDInDB, getLastIDError := e.DBAdapter.GetBlocksTableLastID()
+	//Get last block ID that is fully saved
+	lastBlockIDInDB, savedChainID, getLastIDError := e.getSyncedHeight()
 	if getLastIDError != nil {
-		println("error on reading last block id from db: " + getLastIDError.Error())
-		lastBlockIDInDB = 0
+		return fmt.Errorf("error on reading last block id from db: %w", getLastIDError)
+	}
+
+	//powers of bond and unbond txs are counted from genesis set and balances from genesis accounts
+	genesisErr := e.saveGenesis()
+	if genesisErr != nil {
+		return genesisErr
+	}
+	e.syncValidatorKeys()
+
+	//accounts are only a side index, they are loaded again on next update if it fails
+	loadedAccs, loadAccountsErr := e.loadAccounts()
+
+	//state of heights that are synced without executions is derived before new blocks are saved
+	derivedHeight, deriveErr := e.deriveMissingState(lastBlockIDInDB)
+	if deriveErr == errRolledBack {
+		return nil
+	}
+	if deriveErr != nil {
+		return deriveErr
 	}
 
 	/*
@@ -79,8 +118,7 @@ func (e *Explorer) UpdateAll() error {
 
 	if currentHeight > lastBlockIDInDB {
 		d := currentHeight - lastBlockIDInDB
-		n := int(d / 1000)
-		if d > 1000 {
+		if d > blocksPageSize {
 			s := lastBlockIDInDB
 			if s == 0 {
 				s = 1
@@ -88,30 +126,96 @@ func (e *Explorer) UpdateAll() error {
 			println("Saving new blocks number", s, " to ", currentHeight, " in database...")
 		}
 
-		var startIndex uint64
-		var endIndex uint64
-
 		startBlockID := lastBlockIDInDB + 1
 
-		for i := 0; i <= n; i++ {
-			startIndex = startBlockID + uint64(i*1000)
-			endIndex = startIndex + 999
-			if endIndex > currentHeight {
-				endIndex = currentHeight
+		pipeline, pipelineErr := e.newPipeline()
+		if pipelineErr != nil {
+			return pipelineErr
+		}
+		n := pipeline.numPages(startBlockID, currentHeight)
+		touched := make(map[string]bool)
+		//syncedHeight is last height that is committed, it goes back to common ancestor after a fork
+		syncedHeight := lastBlockIDInDB
+
+		syncErr := pipeline.run(startBlockID, currentHeight, func(page *blockPage) error {
+			blocks := page.blocks
+			if savedChainID != "" && blocks[0].ChainID != savedChainID {
+				return fmt.Errorf("database is synced with chain %s but node is on chain %s", savedChainID, blocks[0].ChainID)
+			}
+
+			forked, ancestor, forkErr := e.detectFork(blocks[0])
+			if forkErr != nil {
+				return forkErr
+			}
+			if forked {
+				rollbackErr := e.rollbackTo(ancestor, blocks[0].ChainID)
+				if rollbackErr != nil {
+					return rollbackErr
+				}
+				println("\nchain is forked, blocks after", ancestor, "are rolled back")
+				syncedHeight = uint64(ancestor)
+				if derivedHeight > syncedHeight {
+					derivedHeight = syncedHeight
+				}
+				return errRolledBack
 			}
 
-			blocks, _ := e.BCAdapter.GetBlocks(startIndex, endIndex)
-			savingErr := e.saveBlocksInDB(blocks, e.BCAdapter, e.DBAdapter)
+			//duration of last block is only known by node, it is read before page transaction is opened
+			var syncInfo *bc.StatusSyncInfo
+			if page.to == currentHeight {
+				info, errGetSyncInfo := e.BCAdapter.GetSyncInfo()
+				if errGetSyncInfo != nil {
+					return fmt.Errorf("error on get sync info: %w", errGetSyncInfo)
+				}
+				syncInfo = info
+			}
+
+			//state is only derived on top of state of previous heights, so a gap is never skipped
+			derive := page.hasExecutions && derivedHeight+1 == page.from
+			savingErr := e.saveBatchInDB(page, syncInfo, derive)
 			if savingErr != nil {
-				println("error on saving blocks in db: " + savingErr.Error())
-				return savingErr
+				return fmt.Errorf("error on saving blocks %d to %d in db: %w", page.from, page.to, savingErr)
+			}
+			addTouchedAddresses(page, touched)
+			syncedHeight = page.to
+			if derive {
+				derivedHeight = page.to
 			}
 
-			perc := (int)((float64(i+1) / float64(n+1)) * 100.0)
-			fmt.Printf("\r%d%% saved! (%d/%d)", perc, endIndex-startBlockID, d)
+			perc := (int)((float64(page.index+1) / float64(n)) * 100.0)
+			fmt.Printf("\r%d%% saved! (%d/%d)", perc, page.to-startBlockID+1, d)
+			return nil
+		})
+		//blocks after common ancestor will be saved on next update, pages that are
+		//committed before fork still need their accounts and rich list refreshed
+		rolledBack := syncErr == errRolledBack
+		if rolledBack {
+			syncErr = nil
 		}
 
-		if d > 1000 {
+		//loaded accounts are already latest, refreshing them before loading succeeds
+		//would stop accounts that are only in genesis from being loaded
+		accs := loadedAccs
+		if accs == nil && loadAccountsErr == nil {
+			accs = e.refreshAccounts(touched)
+		}
+		if syncErr != nil {
+			return syncErr
+		}
+		//balances and validators are only reconciled when synced height is still last block of node
+		//and their changes are derived up to it, otherwise txs of a gap would be counted twice
+		if derivedHeight == syncedHeight {
+			e.reconcileBalances(accs, syncedHeight)
+			e.reconcileValidators(syncedHeight)
+		}
+
+		e.refreshRichList()
+
+		if rolledBack {
+			return nil
+		}
+
+		if d > blocksPageSize {
 			println("\r", d, "new blocks saved!                   ")
 			println("Checking new blocks...")
 		} else {
@@ -125,59 +229,374 @@ func (e *Explorer) UpdateAll() error {
 	return nil
 }
 
-func (e *Explorer) saveBlocksInDB(blocks []bc.BlockInfo, bcAdapter bc.Adapter, dbAdapter db.Adapter) error {
+//saveGenesis saves validator set and account balances of genesis once, later changes of set
+//are derived from bond and unbond txs and changes of balances from txs while saving blocks
+func (e *Explorer) saveGenesis() error {
+	savedValidators, err := e.DBAdapter.HasGenesisValidators()
+	if err != nil {
+		return fmt.Errorf("error on reading genesis validators from db: %w", err)
+	}
+	savedBalances, err := e.DBAdapter.HasGenesisBalances()
+	if err != nil {
+		return fmt.Errorf("error on reading genesis balances from db: %w", err)
+	}
+	if savedValidators && savedBalances {
+		return nil
+	}
+
+	genesis, err := e.BCAdapter.GetGenesis()
+	if err != nil {
+		return fmt.Errorf("error on reading genesis: %w", err)
+	}
+
+	if !savedValidators {
+		err = e.DBAdapter.SaveGenesisValidators(genesis.Validators)
+		if err != nil {
+			return fmt.Errorf("error on saving genesis validators in db: %w", err)
+		}
+	}
+	if !savedBalances {
+		err = e.DBAdapter.SaveGenesisBalances(genesis.Accounts)
+		if err != nil {
+			return fmt.Errorf("error on saving genesis balances in db: %w", err)
+		}
+	}
+	return nil
+}
+
+//syncValidatorKeys saves public keys of current validators, they are not known for validators
+//that are bonded after genesis. Errors are only logged so syncing blocks goes on
+func (e *Explorer) syncValidatorKeys() {
+	set, err := e.BCAdapter.GetValidators()
+	if err != nil {
+		println("error on reading validators: " + err.Error())
+		return
+	}
+
+	err = e.DBAdapter.SaveValidatorKeys(set.Validators)
+	if err != nil {
+		println("error on saving validator keys in db: " + err.Error())
+	}
+}
+
+//reconcileValidators checks derived validator set against set of node, it is only done when set of node
+//is at synced height. Errors are only logged so set is reconciled again on next update
+func (e *Explorer) reconcileValidators(height uint64) {
+	set, err := e.BCAdapter.GetValidators()
+	if err != nil {
+		println("error on reading validators: " + err.Error())
+		return
+	}
+	if set.Height != int64(height) {
+		return
+	}
+
+	err = e.DBAdapter.ReconcileValidators(set.Validators, set.Height)
+	if err != nil {
+		println("error on reconciling validators in db: " + err.Error())
+	}
+}
+
+//detectFork checks parent hash of block against saved block at previous height
+//and returns height of common ancestor if saved chain has diverged
+func (e *Explorer) detectFork(block bc.BlockInfo) (bool, int64, error) {
+	if block.Height <= 1 || block.LastBlockHash == "" {
+		return false, 0, nil
+	}
+
+	savedHash, err := e.DBAdapter.GetBlockHash(block.Height - 1)
+	if err != nil {
+		return false, 0, fmt.Errorf("error on reading hash of block %d from db: %w", block.Height-1, err)
+	}
+	if savedHash == "" || strings.EqualFold(savedHash, block.LastBlockHash) {
+		return false, 0, nil
+	}
+
+	ancestor, err := e.findCommonAncestor(block.Height - 2)
+	if err != nil {
+		return false, 0, err
+	}
+	return true, ancestor, nil
+}
+
+//findCommonAncestor goes back from height and returns last block that has same hash
+//in database and blockchain. Max reorg depth of zero or less means no limit
+func (e *Explorer) findCommonAncestor(height int64) (int64, error) {
+	maxDepth := int64(e.Config.App.MaxReorgDepth)
+	for h := height; h > 0; h-- {
+		if maxDepth > 0 && height-h >= maxDepth {
+			return 0, fmt.Errorf("no common ancestor found in last %d blocks before block %d", maxDepth, height+1)
+		}
+
+		savedHash, err := e.DBAdapter.GetBlockHash(h)
+		if err != nil {
+			return 0, fmt.Errorf("error on reading hash of block %d from db: %w", h, err)
+		}
+
+		inf, err := e.BCAdapter.GetBlockInfo(uint64(h))
+		if err != nil {
+			return 0, fmt.Errorf("error on reading block %d: %w", h, err)
+		}
+
+		if strings.EqualFold(savedHash, inf.BlockHash) {
+			return h, nil
+		}
+	}
+	//rolling back whole database is never done silently
+	return 0, fmt.Errorf("no common ancestor found before block %d", height+1)
+}
+
+//rollbackTo removes all blocks after ancestor, they will be saved again on next update
+func (e *Explorer) rollbackTo(ancestor int64, chainID string) error {
+	err := e.runInDBTx(func(dbTx db.TxAdapter) error {
+		err := dbTx.RollbackBlocks(ancestor + 1)
+		if err != nil {
+			return err
+		}
+		return dbTx.UpdateSyncState(chainID, ancestor)
+	})
+	if err != nil {
+		return fmt.Errorf("error on rolling back blocks after %d: %w", ancestor, err)
+	}
+	return nil
+}
+
+//getSyncedHeight returns last fully saved height and its chain from sync checkpoint.
+//Databases that are synced before checkpoint existed continue from last saved block
+func (e *Explorer) getSyncedHeight() (uint64, string, error) {
+	state, err := e.DBAdapter.GetSyncState()
+	if err != nil {
+		return 0, "", err
+	}
+	if state != nil {
+		return state.LastHeight, state.ChainID, nil
+	}
+
+	lastID, err := e.DBAdapter.GetBlocksTableLastID()
+	if err != nil {
+		return 0, "", err
+	}
+	return lastID, "", nil
+}
+
+//runInDBTx runs fn inside a database transaction and commits it if fn succeeds
+func (e *Explorer) runInDBTx(fn func(dbTx db.TxAdapter) error) error {
+	dbTx, beginErr := e.DBAdapter.Begin()
+	if beginErr != nil {
+		return fmt.Errorf("error on starting db transaction: %w", beginErr)
+	}
+
+	err := fn(dbTx)
+	if err != nil {
+		dbTx.Rollback()
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+//saveBatchInDB saves a page of blocks and moves sync checkpoint inside one database
+//transaction, so a failure never leaves a block with only some of its txs in database.
+//State that is derived from txs is only saved with derive, otherwise page is left after
+//derived height. Duration of last block is saved if it is latest block in sync info, sync info may be nil
+func (e *Explorer) saveBatchInDB(page *blockPage, syncInfo *bc.StatusSyncInfo, derive bool) error {
+	return e.runInDBTx(func(dbTx db.TxAdapter) error {
+		err := e.saveBlocksInDB(page, dbTx)
+		if err != nil {
+			return err
+		}
+
+		if derive {
+			err = e.saveDerivedStateInDB(page, dbTx)
+			if err != nil {
+				return err
+			}
+		}
+
+		last := page.blocks[len(page.blocks)-1]
+		if syncInfo != nil && int64(syncInfo.LatestBlockHeight) == last.Height {
+			err = dbTx.UpdateBlockDuration(last.Height, syncInfo.LatestBlockDuration)
+			if err != nil {
+				return fmt.Errorf("error on update block duration: %w", err)
+			}
+		}
+
+		err = dbTx.UpdateSyncState(last.ChainID, last.Height)
+		if err != nil || !derive {
+			return err
+		}
+		return dbTx.UpdateDerivedHeight(last.Height)
+	})
+}
+
+//saveBlocksInDB saves blocks of page with their txs and commit signatures
+func (e *Explorer) saveBlocksInDB(page *blockPage, dbAdapter db.Adapter) error {
+	blocks := page.blocks
 	l := len(blocks)
 	if l <= 0 {
 		return fmt.Errorf("Empty Blocks Array")
 	}
+
+	if e.Config.App.BulkInsert {
+		errBulkSave := e.saveBlocksInDBBulk(page, dbAdapter)
+		if errBulkSave != nil {
+			return fmt.Errorf("error on bulk save blocks in db: %w", errBulkSave)
+		}
+	} else {
+		errSave := e.saveBlocksInDBByRow(page, dbAdapter)
+		if errSave != nil {
+			return errSave
+		}
+	}
+
+	if e.Config.App.TrackSignatures {
+		errSignatures := dbAdapter.InsertCommitSignatures(blocks)
+		if errSignatures != nil {
+			return fmt.Errorf("error on save commit signatures in db: %w", errSignatures)
+		}
+	}
+
+	return nil
+}
+
+//saveDerivedStateInDB saves executions, contracts, names, permission changes, validator powers and balance
+//changes of page. They are derived from executions and from state that is saved before page, so pages must
+//be saved in height order and a page without executions is refused
+func (e *Explorer) saveDerivedStateInDB(page *blockPage, dbAdapter db.Adapter) error {
+	if !page.hasExecutions {
+		return fmt.Errorf("state of blocks %d to %d can not be derived without executions of their txs", page.from, page.to)
+	}
+
+	errExecutions := e.saveTxExecutionsInDB(page, dbAdapter)
+	if errExecutions != nil {
+		return fmt.Errorf("error on save tx executions in db: %w", errExecutions)
+	}
+
+	errContracts := e.saveContractsInDB(page, dbAdapter)
+	if errContracts != nil {
+		return fmt.Errorf("error on save contracts in db: %w", errContracts)
+	}
+
+	errNames := e.saveNamesInDB(page, dbAdapter)
+	if errNames != nil {
+		return fmt.Errorf("error on save names in db: %w", errNames)
+	}
+
+	errPermissions := e.savePermissionChangesInDB(page, dbAdapter)
+	if errPermissions != nil {
+		return fmt.Errorf("error on save permission changes in db: %w", errPermissions)
+	}
+
+	errPowers := e.saveValidatorPowerChangesInDB(page, dbAdapter)
+	if errPowers != nil {
+		return fmt.Errorf("error on save validator power changes in db: %w", errPowers)
+	}
+
+	errBalances := e.saveBalanceChangesInDB(page, dbAdapter)
+	if errBalances != nil {
+		return fmt.Errorf("error on save balance changes in db: %w", errBalances)
+	}
+
+	return nil
+}
+
+//saveBlocksInDBByRow saves blocks and their transactions one row at a time
+func (e *Explorer) saveBlocksInDBByRow(page *blockPage, dbAdapter db.Adapter) error {
+	blocks := page.blocks
+	l := len(blocks)
 	for i := 0; i < l; i++ {
 		block := blocks[i]
 		err := dbAdapter.InsertBlock(&block)
 		if err != nil {
-			println("error on insert block in db: " + err.Error())
-			return err
+			return fmt.Errorf("error on insert block %d in db: %w", block.Height, err)
 		}
 		if block.NumTxs > 0 {
-			errTxSave := e.saveBlockTXsInDB(block, bcAdapter, dbAdapter)
+			errTxSave := e.saveBlockTXsInDB(block, page.txs[block.Height], dbAdapter)
 			if errTxSave != nil {
-				println("error on save block txs in db: " + errTxSave.Error())
-				return errTxSave
+				return fmt.Errorf("error on save block txs in db: %w", errTxSave)
 			}
 		}
 	}
 
-	syncInfo, errGetSyncInfo := bcAdapter.GetSyncInfo()
-	if errGetSyncInfo != nil {
-		println("error on get sync info: " + errGetSyncInfo.Error())
-		return errGetSyncInfo
+	return nil
+}
+
+//saveBlocksInDBBulk saves all blocks and transactions of page with bulk inserts
+func (e *Explorer) saveBlocksInDBBulk(page *blockPage, dbAdapter db.Adapter) error {
+	txs := make([]bc.Transaction, 0)
+	for _, block := range page.blocks {
+		if block.NumTxs <= 0 {
+			continue
+		}
+
+		blockTxs := page.txs[block.Height]
+		if int64(len(blockTxs)) != block.NumTxs {
+			return fmt.Errorf("error on parsing txs for block %v some txs are missed", block.Height)
+		}
+		//same order as saving one by one
+		for i := len(blockTxs) - 1; i >= 0; i-- {
+			txs = append(txs, blockTxs[i])
+		}
+	}
+
+	err := dbAdapter.InsertBlocksBulk(page.blocks)
+	if err != nil {
+		return err
+	}
+
+	return dbAdapter.InsertTxsBulk(txs)
+}
+
+//saveTxExecutionsInDB saves execution results of all transactions of page, they are saved
+//after transactions so each execution is linked to its transaction
+func (e *Explorer) saveTxExecutionsInDB(page *blockPage, dbAdapter db.Adapter) error {
+	execs := make([]bc.TxExecution, 0)
+	for _, block := range page.blocks {
+		execs = append(execs, page.executions[block.Height]...)
+	}
+	return dbAdapter.InsertTxExecutions(execs)
+}
+
+//saveContractsInDB saves contracts that are deployed by transactions of page
+func (e *Explorer) saveContractsInDB(page *blockPage, dbAdapter db.Adapter) error {
+	contracts := make([]bc.Contract, 0)
+	for _, block := range page.blocks {
+		contracts = append(contracts, page.contracts[block.Height]...)
 	}
+	return dbAdapter.InsertContracts(contracts)
+}
 
-	blockID := int64(syncInfo.LatestBlockHeight)
-	if blockID==blocks[l-1].Height {
-		duration := syncInfo.LatestBlockDuration
-		errUpdateDuration := dbAdapter.UpdateBlockDuration(blockID, duration)
-		if errUpdateDuration != nil {
-			println("error on update block duration: " + errUpdateDuration.Error())
-			return errUpdateDuration
+//saveBalanceChangesInDB saves balance changes that are caused by transactions of page
+func (e *Explorer) saveBalanceChangesInDB(page *blockPage, dbAdapter db.Adapter) error {
+	changes := make([]bc.BalanceChange, 0)
+	for _, block := range page.blocks {
+		if block.NumTxs <= 0 {
+			continue
 		}
+		changes = append(changes, bc.BalanceChanges(page.txs[block.Height], page.executions[block.Height], page.contracts[block.Height])...)
 	}
+	return dbAdapter.InsertBalanceChanges(changes)
+}
 
-	return nil
+//succeededTxHashes returns upper case hashes of txs whose execution succeeded. Outcome of a tx
+//without execution is unknown, so it is not in them and nothing is derived from it
+func succeededTxHashes(execs []bc.TxExecution) map[string]bool {
+	succeeded := make(map[string]bool)
+	for _, exec := range execs {
+		if exec.Succeeded {
+			succeeded[strings.ToUpper(exec.TxHash)] = true
+		}
+	}
+	return succeeded
 }
 
-func (e *Explorer) saveBlockTXsInDB(block bc.BlockInfo, bcAdapter bc.Adapter, dbAdapter db.Adapter) error {
+func (e *Explorer) saveBlockTXsInDB(block bc.BlockInfo, txs []bc.Transaction, dbAdapter db.Adapter) error {
 	l := block.NumTxs
 	if l <= 0 {
 		return fmt.Errorf("Empty Transactions Array")
 	}
 
 	height := uint64(block.Height)
-	txs, errTXs := bcAdapter.GetTXs(height)
-	if errTXs != nil {
-		println("error on get block txs: " + errTXs.Error())
-		return errTXs
-	}
-
 	if int64(len(txs)) != l {
 		return fmt.Errorf("error on parsing txs for block %v some txs are missed", height)
 	}
@@ -185,8 +604,7 @@ func (e *Explorer) saveBlockTXsInDB(block bc.BlockInfo, bcAdapter bc.Adapter, db
 	for i := l - 1; i >= 0; i-- {
 		err := dbAdapter.InsertTx(&txs[i])
 		if err != nil {
-			println("error on save tx in db: " + err.Error())
-			return err
+			return fmt.Errorf("error on save tx %s in db: %w", txs[i].Hash, err)
 		}
 	}
 	return nil
@@ -0,0 +1,686 @@
+package explorer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	config "github.com/BurrowBlocks/config"
+	db "github.com/BurrowBlocks/database"
+	"github.com/stretchr/testify/require"
+)
+
+//fakeSyncDB returns saved sync checkpoint and last block. Methods that are not overridden are not used
+type fakeSyncDB struct {
+	db.Adapter
+	state     *db.SyncState
+	lastBlock uint64
+	err       error
+}
+
+func (f *fakeSyncDB) GetSyncState() (*db.SyncState, error) {
+	return f.state, f.err
+}
+
+func (f *fakeSyncDB) GetBlocksTableLastID() (uint64, error) {
+	return f.lastBlock, nil
+}
+
+func TestGetSyncedHeight(t *testing.T) {
+	dbErr := errors.New("database is down")
+	tests := []struct {
+		name    string
+		db      *fakeSyncDB
+		height  uint64
+		chainID string
+		err     error
+	}{
+		{name: "checkpoint", db: &fakeSyncDB{state: &db.SyncState{ChainID: "C1", LastHeight: 7}, lastBlock: 9},
+			height: 7, chainID: "C1"},
+		{name: "synced before checkpoint", db: &fakeSyncDB{lastBlock: 9}, height: 9},
+		{name: "empty", db: &fakeSyncDB{}},
+		{name: "error", db: &fakeSyncDB{lastBlock: 9, err: dbErr}, err: dbErr},
+	}
+
+	for _, test := range tests {
+		e := &Explorer{DBAdapter: test.db}
+		height, chainID, err := e.getSyncedHeight()
+		require.Equal(t, test.err, err, test.name)
+		require.Equal(t, test.height, height, test.name)
+		require.Equal(t, test.chainID, chainID, test.name)
+	}
+}
+
+//fakeBulkDB keeps blocks and txs that are bulk inserted
+type fakeBulkDB struct {
+	db.Adapter
+	blocks []bc.BlockInfo
+	txs    []bc.Transaction
+}
+
+func (f *fakeBulkDB) InsertBlocksBulk(blocks []bc.BlockInfo) error {
+	f.blocks = append(f.blocks, blocks...)
+	return nil
+}
+
+func (f *fakeBulkDB) InsertTxsBulk(txs []bc.Transaction) error {
+	f.txs = append(f.txs, txs...)
+	return nil
+}
+
+func TestSaveBlocksInDBBulk(t *testing.T) {
+	page := &blockPage{
+		blocks: []bc.BlockInfo{{Height: 1, NumTxs: 2}, {Height: 2}, {Height: 3, NumTxs: 1}},
+		txs: map[int64][]bc.Transaction{
+			1: {{Hash: "T2"}, {Hash: "T1"}},
+			3: {{Hash: "T3"}},
+		},
+	}
+
+	dbAdapter := &fakeBulkDB{}
+	require.NoError(t, (&Explorer{}).saveBlocksInDBBulk(page, dbAdapter))
+	require.Equal(t, page.blocks, dbAdapter.blocks)
+	hashes := make([]string, 0)
+	for _, tx := range dbAdapter.txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	require.Equal(t, []string{"T1", "T2", "T3"}, hashes)
+
+	//nothing is saved when txs of a block are missed
+	page.txs[3] = nil
+	dbAdapter = &fakeBulkDB{}
+	require.Error(t, (&Explorer{}).saveBlocksInDBBulk(page, dbAdapter))
+	require.Empty(t, dbAdapter.blocks)
+	require.Empty(t, dbAdapter.txs)
+}
+
+//memState is data of memDB, a transaction works on a copy of it that replaces it on commit
+type memState struct {
+	blocks    map[int64]bc.BlockInfo
+	txs       map[string]bc.Transaction
+	state     *db.SyncState
+	accounts  map[string]bc.Account
+	durations map[int64]uint64
+	//executions and derived state are only recorded in order they are saved
+	executions []bc.TxExecution
+	names      []bc.NameEntry
+	contracts  []bc.Contract
+	perms      []bc.PermissionChange
+	powers     []bc.ValidatorPowerChange
+	changes    int
+}
+
+func (s *memState) copy() *memState {
+	c := &memState{
+		blocks:    make(map[int64]bc.BlockInfo),
+		txs:       make(map[string]bc.Transaction),
+		accounts:  make(map[string]bc.Account),
+		durations: make(map[int64]uint64),
+	}
+	for h, b := range s.blocks {
+		c.blocks[h] = b
+	}
+	for hash, tx := range s.txs {
+		c.txs[hash] = tx
+	}
+	for address, acc := range s.accounts {
+		c.accounts[address] = acc
+	}
+	for h, d := range s.durations {
+		c.durations[h] = d
+	}
+	if s.state != nil {
+		state := *s.state
+		c.state = &state
+	}
+	c.executions = append(c.executions, s.executions...)
+	c.names = append(c.names, s.names...)
+	c.contracts = append(c.contracts, s.contracts...)
+	c.perms = append(c.perms, s.perms...)
+	c.powers = append(c.powers, s.powers...)
+	c.changes = s.changes
+	return c
+}
+
+//memDB keeps blocks, txs, sync checkpoint and accounts in memory. Derived data is not kept,
+//methods that are not overridden are not used
+type memDB struct {
+	db.Adapter
+	data *memState
+	//parent is adapter that transaction is started on, it is nil outside transactions
+	parent *memDB
+	//failTxsAt fails saving txs of a page that has a tx at this height
+	failTxsAt      int64
+	rolledBackFrom int64
+	reconciledAt   int64
+	richListCount  int
+	//validators are last set that is reconciled at validatorsReconciledAt
+	validators             []bc.Validator
+	validatorsReconciledAt int64
+	//openTxs is number of transactions that are started and not finished yet
+	openTxs int
+}
+
+func newMemDB() *memDB {
+	return &memDB{data: (&memState{}).copy(), rolledBackFrom: -1, reconciledAt: -1, validatorsReconciledAt: -1}
+}
+
+//saveBlocks saves blocks 1..height of chain as if they are synced
+func (m *memDB) saveBlocks(chain *memChain, height int64) {
+	for h := int64(1); h <= height; h++ {
+		m.data.blocks[h] = chain.block(h)
+	}
+	m.data.state = &db.SyncState{ChainID: "C1", LastHeight: uint64(height), DerivedHeight: uint64(height)}
+}
+
+func (m *memDB) Begin() (db.TxAdapter, error) {
+	m.openTxs++
+	return &memDB{data: m.data.copy(), parent: m, failTxsAt: m.failTxsAt, rolledBackFrom: -1}, nil
+}
+
+func (m *memDB) Commit() error {
+	m.parent.openTxs--
+	m.parent.data = m.data
+	if m.rolledBackFrom >= 0 {
+		m.parent.rolledBackFrom = m.rolledBackFrom
+	}
+	return nil
+}
+
+func (m *memDB) Rollback() error {
+	m.parent.openTxs--
+	return nil
+}
+
+func (m *memDB) GetSyncState() (*db.SyncState, error) {
+	return m.data.state, nil
+}
+
+func (m *memDB) GetBlocksTableLastID() (uint64, error) {
+	var last int64
+	for h := range m.data.blocks {
+		if h > last {
+			last = h
+		}
+	}
+	return uint64(last), nil
+}
+
+func (m *memDB) UpdateSyncState(chainID string, height int64) error {
+	state := &db.SyncState{ChainID: chainID, LastHeight: uint64(height)}
+	//derived height goes back with synced height after a rollback
+	if m.data.state != nil {
+		state.DerivedHeight = m.data.state.DerivedHeight
+		if state.DerivedHeight > state.LastHeight {
+			state.DerivedHeight = state.LastHeight
+		}
+	}
+	m.data.state = state
+	return nil
+}
+
+func (m *memDB) UpdateDerivedHeight(height int64) error {
+	m.data.state.DerivedHeight = uint64(height)
+	return nil
+}
+
+func (m *memDB) RollbackDerivedState(fromHeight int64) error {
+	executions := make([]bc.TxExecution, 0)
+	for _, exec := range m.data.executions {
+		if exec.Height < fromHeight {
+			executions = append(executions, exec)
+		}
+	}
+	names := make([]bc.NameEntry, 0)
+	for _, n := range m.data.names {
+		if n.Height < fromHeight {
+			names = append(names, n)
+		}
+	}
+	contracts := make([]bc.Contract, 0)
+	for _, c := range m.data.contracts {
+		if c.Height < fromHeight {
+			contracts = append(contracts, c)
+		}
+	}
+	perms := make([]bc.PermissionChange, 0)
+	for _, c := range m.data.perms {
+		if c.Height < fromHeight {
+			perms = append(perms, c)
+		}
+	}
+	powers := make([]bc.ValidatorPowerChange, 0)
+	for _, c := range m.data.powers {
+		if c.Height < fromHeight {
+			powers = append(powers, c)
+		}
+	}
+	m.data.executions, m.data.names, m.data.contracts = executions, names, contracts
+	m.data.perms, m.data.powers = perms, powers
+	if m.data.state.DerivedHeight >= uint64(fromHeight) {
+		m.data.state.DerivedHeight = uint64(fromHeight - 1)
+	}
+	return nil
+}
+
+func (m *memDB) HasGenesisValidators() (bool, error) {
+	return true, nil
+}
+
+func (m *memDB) HasGenesisBalances() (bool, error) {
+	return true, nil
+}
+
+func (m *memDB) SaveValidatorKeys(validators []bc.Validator) error {
+	return nil
+}
+
+func (m *memDB) ReconcileValidators(validators []bc.Validator, height int64) error {
+	m.validators = validators
+	m.validatorsReconciledAt = height
+	return nil
+}
+
+func (m *memDB) GetAccountsTableLastID() (uint64, error) {
+	return uint64(len(m.data.accounts)), nil
+}
+
+func (m *memDB) SaveAccounts(accs []*bc.Account) error {
+	for _, acc := range accs {
+		m.data.accounts[acc.Address] = *acc
+	}
+	return nil
+}
+
+func (m *memDB) ReconcileBalances(accs []*bc.Account, height int64) error {
+	m.reconciledAt = height
+	return nil
+}
+
+func (m *memDB) RefreshRichList() error {
+	m.richListCount++
+	return nil
+}
+
+func (m *memDB) GetBlockHash(height int64) (string, error) {
+	return m.data.blocks[height].BlockHash, nil
+}
+
+func (m *memDB) RollbackBlocks(fromHeight int64) error {
+	for h := range m.data.blocks {
+		if h >= fromHeight {
+			delete(m.data.blocks, h)
+		}
+	}
+	for hash, tx := range m.data.txs {
+		if tx.BlockID >= fromHeight {
+			delete(m.data.txs, hash)
+		}
+	}
+	m.rolledBackFrom = fromHeight
+	return nil
+}
+
+func (m *memDB) InsertBlocksBulk(blocks []bc.BlockInfo) error {
+	for _, b := range blocks {
+		m.data.blocks[b.Height] = b
+	}
+	return nil
+}
+
+func (m *memDB) InsertTxsBulk(txs []bc.Transaction) error {
+	for _, tx := range txs {
+		if tx.BlockID == m.failTxsAt {
+			return errors.New("database is down")
+		}
+		m.data.txs[tx.Hash] = tx
+	}
+	return nil
+}
+
+func (m *memDB) InsertBlock(b *bc.BlockInfo) error {
+	m.data.blocks[b.Height] = *b
+	return nil
+}
+
+func (m *memDB) InsertTx(tx *bc.Transaction) error {
+	if tx.BlockID == m.failTxsAt {
+		return errors.New("database is down")
+	}
+	m.data.txs[tx.Hash] = *tx
+	return nil
+}
+
+func (m *memDB) UpdateBlockDuration(height int64, duration uint64) error {
+	m.data.durations[height] = duration
+	return nil
+}
+
+func (m *memDB) GetMissingBlockRanges(maxHeight uint64) ([]db.HeightRange, error) {
+	heights := make([]uint64, 0)
+	for h := uint64(1); h <= maxHeight; h++ {
+		if _, ok := m.data.blocks[int64(h)]; !ok {
+			heights = append(heights, h)
+		}
+	}
+	return heightsToRanges(heights), nil
+}
+
+func (m *memDB) GetBlocksWithMissingTxs(maxHeight uint64) ([]uint64, error) {
+	return nil, nil
+}
+
+func (m *memDB) InsertTxExecutions(execs []bc.TxExecution) error {
+	m.data.executions = append(m.data.executions, execs...)
+	return nil
+}
+
+func (m *memDB) InsertContracts(contracts []bc.Contract) error {
+	m.data.contracts = append(m.data.contracts, contracts...)
+	return nil
+}
+
+func (m *memDB) GetName(name string) (*db.Name, error) {
+	for i := len(m.data.names) - 1; i >= 0; i-- {
+		n := m.data.names[i]
+		if n.Name == name {
+			return &db.Name{Name: n.Name, Data: n.Data, Owner: n.Owner, Expires: n.Expires, Height: n.Height, TxHash: n.TxHash}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memDB) InsertNames(entries []bc.NameEntry) error {
+	m.data.names = append(m.data.names, entries...)
+	return nil
+}
+
+func (m *memDB) InsertPermissionChanges(changes []bc.PermissionChange) error {
+	m.data.perms = append(m.data.perms, changes...)
+	m.data.changes += len(changes)
+	return nil
+}
+
+func (m *memDB) GetValidatorPower(address string, beforeHeight int64) (uint64, error) {
+	return 0, nil
+}
+
+func (m *memDB) InsertValidatorPowerChanges(changes []bc.ValidatorPowerChange) error {
+	m.data.powers = append(m.data.powers, changes...)
+	m.data.changes += len(changes)
+	return nil
+}
+
+func (m *memDB) InsertBalanceChanges(changes []bc.BalanceChange) error {
+	m.data.changes += len(changes)
+	return nil
+}
+
+//memChain is a chain of blocks 1..height whose block h has hash Hh and one SendTx from A<h> to B<h>.
+//Hashes in forked are returned instead when a block is read by its height after fork
+type memChain struct {
+	bc.Adapter
+	height uint64
+	//forkedParents are parent hashes of blocks that are fetched after chain is forked
+	forkedParents map[int64]string
+	forked        map[int64]string
+	//txs are returned instead of SendTx of a block, all txs succeed
+	txs        map[int64][]bc.Transaction
+	accounts   []*bc.Account
+	validators *bc.ValidatorSet
+	//noExecutions is set when node does not serve tx executions
+	noExecutions bool
+	//logs are emitted by execution of each tx
+	logs []bc.TxEvent
+	//openTxsOnSyncInfo keeps open transactions of dbAdapter each time sync info is read
+	dbAdapter         *memDB
+	openTxsOnSyncInfo []int
+}
+
+func (c *memChain) hash(h int64) string {
+	if hash, ok := c.forked[h]; ok {
+		return hash
+	}
+	return fmt.Sprintf("H%d", h)
+}
+
+func (c *memChain) block(h int64) bc.BlockInfo {
+	parent := fmt.Sprintf("H%d", h-1)
+	if hash, ok := c.forkedParents[h]; ok {
+		parent = hash
+	}
+	return bc.BlockInfo{ChainID: "C1", Height: h, BlockHash: fmt.Sprintf("H%d", h), LastBlockHash: parent, NumTxs: 1}
+}
+
+func (c *memChain) Update() error {
+	return nil
+}
+
+func (c *memChain) GetBlocksLastHeight() (uint64, error) {
+	return c.height, nil
+}
+
+func (c *memChain) GetBlocks(from uint64, to uint64) ([]bc.BlockInfo, error) {
+	blocks := make([]bc.BlockInfo, 0)
+	for h := from; h <= to; h++ {
+		blocks = append(blocks, c.block(int64(h)))
+	}
+	return blocks, nil
+}
+
+func (c *memChain) GetBlockInfo(height uint64) (*bc.BlockInfo, error) {
+	b := c.block(int64(height))
+	b.BlockHash = c.hash(int64(height))
+	return &b, nil
+}
+
+func (c *memChain) GetTXs(height uint64) ([]bc.Transaction, error) {
+	if txs, ok := c.txs[int64(height)]; ok {
+		return txs, nil
+	}
+	return []bc.Transaction{{Type: "SendTx", BlockID: int64(height), Hash: fmt.Sprintf("T%d", height),
+		From: fmt.Sprintf("A%d", height), To: fmt.Sprintf("B%d", height), Amount: 1}}, nil
+}
+
+func (c *memChain) SupportsTxExecutions() (bool, error) {
+	return !c.noExecutions, nil
+}
+
+func (c *memChain) GetTxExecutions(height uint64) ([]bc.TxExecution, error) {
+	txs, _ := c.GetTXs(height)
+	execs := make([]bc.TxExecution, 0)
+	for i, tx := range txs {
+		exec := bc.TxExecution{TxHash: tx.Hash, Height: int64(height), Index: int64(i), Succeeded: true, Events: c.logs}
+		if bc.IsContractCreation(&tx) {
+			exec.CreatesContract = true
+			exec.ContractAddress = "C" + tx.Hash
+		}
+		execs = append(execs, exec)
+	}
+	return execs, nil
+}
+
+func (c *memChain) GetSyncInfo() (*bc.StatusSyncInfo, error) {
+	if c.dbAdapter != nil {
+		c.openTxsOnSyncInfo = append(c.openTxsOnSyncInfo, c.dbAdapter.openTxs)
+	}
+	return &bc.StatusSyncInfo{LatestBlockHeight: c.height, LatestBlockDuration: 700}, nil
+}
+
+func (c *memChain) GetValidators() (*bc.ValidatorSet, error) {
+	if c.validators == nil {
+		return &bc.ValidatorSet{}, nil
+	}
+	return c.validators, nil
+}
+
+func (c *memChain) GetAccounts() ([]*bc.Account, error) {
+	return c.accounts, nil
+}
+
+func (c *memChain) GetAccount(address string) (*bc.Account, error) {
+	return &bc.Account{Address: address, Balance: 1}, nil
+}
+
+func newMemExplorer(chain *memChain, dbAdapter *memDB) *Explorer {
+	app := config.DefaultAppConfig()
+	app.MaxReorgDepth = 5
+	app.TrackSignatures = false
+	app.RichListInterval = 1
+	return &Explorer{BCAdapter: chain, DBAdapter: dbAdapter, Config: &config.Config{App: app}}
+}
+
+func TestDetectForkOnFirstBlockOfPage(t *testing.T) {
+	chain := &memChain{height: 10}
+	dbAdapter := newMemDB()
+	dbAdapter.saveBlocks(chain, 5)
+	e := newMemExplorer(chain, dbAdapter)
+
+	forked, _, err := e.detectFork(chain.block(6))
+	require.NoError(t, err)
+	require.False(t, forked)
+
+	//saved blocks 4 and 5 are replaced on chain, so parent of block 6 is not saved block 5
+	chain.forked = map[int64]string{4: "X4", 5: "X5"}
+	chain.forkedParents = map[int64]string{6: "X5"}
+	forked, ancestor, err := e.detectFork(chain.block(6))
+	require.NoError(t, err)
+	require.True(t, forked)
+	require.Equal(t, int64(3), ancestor)
+
+	//first block has no saved parent to check
+	forked, _, err = e.detectFork(chain.block(1))
+	require.NoError(t, err)
+	require.False(t, forked)
+}
+
+func TestFindCommonAncestorWalksBack(t *testing.T) {
+	chain := &memChain{height: 20, forked: map[int64]string{8: "X8", 9: "X9", 10: "X10", 11: "X11"}}
+	dbAdapter := newMemDB()
+	dbAdapter.saveBlocks(chain, 12)
+	e := newMemExplorer(chain, dbAdapter)
+
+	ancestor, err := e.findCommonAncestor(11)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), ancestor)
+}
+
+func TestFindCommonAncestorMaxReorgDepth(t *testing.T) {
+	forked := make(map[int64]string)
+	for h := int64(1); h <= 12; h++ {
+		forked[h] = fmt.Sprintf("X%d", h)
+	}
+	chain := &memChain{height: 20, forked: forked}
+	dbAdapter := newMemDB()
+	dbAdapter.saveBlocks(chain, 12)
+	e := newMemExplorer(chain, dbAdapter)
+
+	//blocks 7 to 11 are checked and none of them is common
+	_, err := e.findCommonAncestor(11)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "last 5 blocks")
+
+	//whole chain is never rolled back without a limit either
+	e.Config.App.MaxReorgDepth = 0
+	_, err = e.findCommonAncestor(11)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no common ancestor")
+}
+
+func TestUpdateAllRollsBackFork(t *testing.T) {
+	chain := &memChain{height: 10}
+	dbAdapter := newMemDB()
+	dbAdapter.saveBlocks(chain, 5)
+	chain.forked = map[int64]string{4: "X4", 5: "X5"}
+	chain.forkedParents = map[int64]string{6: "X5"}
+	e := newMemExplorer(chain, dbAdapter)
+
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, int64(4), dbAdapter.rolledBackFrom)
+	require.Equal(t, &db.SyncState{ChainID: "C1", LastHeight: 3, DerivedHeight: 3}, dbAdapter.data.state)
+	require.Len(t, dbAdapter.data.blocks, 3)
+	//ledger is behind node, so balances are not reconciled until blocks after ancestor are saved again
+	require.Equal(t, int64(-1), dbAdapter.reconciledAt)
+}
+
+func TestUpdateAllRefreshesCommittedPagesAfterRollback(t *testing.T) {
+	//chain forks after first page is fetched, so second page does not follow saved first page
+	first := int64(blocksPageSize)
+	chain := &memChain{height: uint64(first + 3),
+		forked:        map[int64]string{first: "X1000", first - 1: "X999"},
+		forkedParents: map[int64]string{first + 1: "X1000"},
+	}
+	dbAdapter := newMemDB()
+	dbAdapter.data.accounts["G1"] = bc.Account{Address: "G1"}
+	e := newMemExplorer(chain, dbAdapter)
+
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, first-1, dbAdapter.rolledBackFrom)
+	require.Equal(t, uint64(first-2), dbAdapter.data.state.LastHeight)
+	require.Len(t, dbAdapter.data.blocks, int(first-2))
+
+	//participants of committed page are refreshed and rich list is recomputed
+	require.Contains(t, dbAdapter.data.accounts, "A1")
+	require.Contains(t, dbAdapter.data.accounts, "B998")
+	require.Equal(t, 1, dbAdapter.richListCount)
+}
+
+func TestUpdateAllSavesPageAtomically(t *testing.T) {
+	for _, bulk := range []bool{true, false} {
+		chain := &memChain{height: blocksPageSize + 500}
+		dbAdapter := newMemDB()
+		dbAdapter.data.accounts["G1"] = bc.Account{Address: "G1"}
+		//block of second page is saved but its tx is not
+		dbAdapter.failTxsAt = blocksPageSize + 200
+		e := newMemExplorer(chain, dbAdapter)
+		e.Config.App.BulkInsert = bulk
+
+		err := e.UpdateAll()
+		require.Error(t, err, "bulk: %v", bulk)
+		require.Contains(t, err.Error(), "database is down")
+
+		//first page is committed and nothing of second page is left
+		require.Len(t, dbAdapter.data.blocks, blocksPageSize, "bulk: %v", bulk)
+		require.Len(t, dbAdapter.data.txs, blocksPageSize, "bulk: %v", bulk)
+		require.Equal(t, uint64(blocksPageSize), dbAdapter.data.state.LastHeight, "bulk: %v", bulk)
+		require.Equal(t, 0, dbAdapter.openTxs, "bulk: %v", bulk)
+
+		//failed page is saved again on next update
+		dbAdapter.failTxsAt = 0
+		require.NoError(t, e.UpdateAll())
+		require.Len(t, dbAdapter.data.blocks, blocksPageSize+500, "bulk: %v", bulk)
+		require.Len(t, dbAdapter.data.txs, blocksPageSize+500, "bulk: %v", bulk)
+	}
+}
+
+func TestUpdateAllReadsSyncInfoOutsideTransaction(t *testing.T) {
+	chain := &memChain{height: blocksPageSize + 5}
+	dbAdapter := newMemDB()
+	dbAdapter.data.accounts["G1"] = bc.Account{Address: "G1"}
+	chain.dbAdapter = dbAdapter
+	e := newMemExplorer(chain, dbAdapter)
+
+	require.NoError(t, e.UpdateAll())
+	//only last page can end at latest block of node
+	require.Equal(t, []int{0}, chain.openTxsOnSyncInfo)
+	require.Equal(t, map[int64]uint64{blocksPageSize + 5: 700}, dbAdapter.data.durations)
+}
+
+func TestUpdateAllReconcilesValidators(t *testing.T) {
+	validators := []bc.Validator{{Address: "V1", Power: 10}, {Address: "V2", Power: 5}}
+	chain := &memChain{height: 10, validators: &bc.ValidatorSet{Height: 10, Validators: validators}}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, int64(10), dbAdapter.validatorsReconciledAt)
+	require.Equal(t, validators, dbAdapter.validators)
+
+	//set of node at another height is not compared with synced blocks
+	chain.height = 12
+	chain.validators = &bc.ValidatorSet{Height: 13, Validators: validators[:1]}
+	require.NoError(t, e.UpdateAll())
+	require.Equal(t, int64(10), dbAdapter.validatorsReconciledAt)
+	require.Equal(t, validators, dbAdapter.validators)
+}
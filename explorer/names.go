@@ -0,0 +1,49 @@
+package explorer
+
+import (
+	"strings"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	db "github.com/BurrowBlocks/database"
+)
+
+//saveNamesInDB applies NameTxs of page on name registry in order of execution and saves entries
+//that they set. Txs whose execution failed or is unknown are skipped
+func (e *Explorer) saveNamesInDB(page *blockPage, dbAdapter db.Adapter) error {
+	entries := make([]bc.NameEntry, 0)
+	latest := make(map[string]*bc.NameEntry)
+
+	for _, block := range page.blocks {
+		succeeded := succeededTxHashes(page.executions[block.Height])
+
+		txs := page.txs[block.Height]
+		for i := range txs {
+			tx := &txs[i]
+			if tx.Type != "NameTx" || !succeeded[strings.ToUpper(tx.Hash)] {
+				continue
+			}
+
+			name := tx.Name
+			prev, ok := latest[name]
+			if !ok {
+				saved, err := dbAdapter.GetName(name)
+				if err != nil {
+					return err
+				}
+				if saved != nil {
+					prev = &bc.NameEntry{Name: saved.Name, Data: saved.Data, Owner: saved.Owner,
+						Expires: saved.Expires, Height: saved.Height, TxHash: saved.TxHash, Deleted: saved.Deleted}
+				}
+			}
+
+			entry, applied := bc.ApplyNameTx(tx, prev)
+			if !applied {
+				continue
+			}
+			latest[name] = entry
+			entries = append(entries, *entry)
+		}
+	}
+
+	return dbAdapter.InsertNames(entries)
+}
@@ -0,0 +1,26 @@
+package explorer
+
+import (
+	"strings"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	db "github.com/BurrowBlocks/database"
+)
+
+//savePermissionChangesInDB saves permission changes that are made by PermsTxs and GovTxs of page.
+//Txs whose execution failed or is unknown are skipped
+func (e *Explorer) savePermissionChangesInDB(page *blockPage, dbAdapter db.Adapter) error {
+	changes := make([]bc.PermissionChange, 0)
+	for _, block := range page.blocks {
+		succeeded := succeededTxHashes(page.executions[block.Height])
+
+		txs := page.txs[block.Height]
+		for i := range txs {
+			if !succeeded[strings.ToUpper(txs[i].Hash)] {
+				continue
+			}
+			changes = append(changes, bc.PermissionChanges(&txs[i])...)
+		}
+	}
+	return dbAdapter.InsertPermissionChanges(changes)
+}
@@ -0,0 +1,308 @@
+package explorer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	config "github.com/BurrowBlocks/config"
+)
+
+//blockPage holds a range of blocks and their transactions fetched from blockchain
+type blockPage struct {
+	index      int
+	from       uint64
+	to         uint64
+	blocks     []bc.BlockInfo
+	txs        map[int64][]bc.Transaction
+	executions map[int64][]bc.TxExecution
+	contracts  map[int64][]bc.Contract
+	//hasExecutions is set when executions of txs are fetched, state is only derived from such pages
+	hasExecutions bool
+	err           error
+}
+
+//fetchPipeline fetches pages of blocks by a pool of workers
+//and passes them to writer in height order
+type fetchPipeline struct {
+	bcAdapter bc.Adapter
+	workers   int
+	depth     int
+	pageSize  uint64
+	//trackSignatures fetches signers of last commit of each block
+	trackSignatures bool
+	//indexExecutions fetches execution results of txs of each block
+	indexExecutions bool
+}
+
+func newFetchPipeline(bcAdapter bc.Adapter, app *config.AppConfig, pageSize uint64) *fetchPipeline {
+	workers := app.FetchWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	depth := app.FetchQueueDepth
+	if depth < 1 {
+		depth = 1
+	}
+	return &fetchPipeline{
+		bcAdapter:       bcAdapter,
+		workers:         workers,
+		depth:           depth,
+		pageSize:        pageSize,
+		trackSignatures: app.TrackSignatures,
+		indexExecutions: app.IndexExecutions,
+	}
+}
+
+//errNoTxExecutions is returned when executions of txs are indexed and node does not serve them
+var errNoTxExecutions = errors.New("node does not serve /tx_executions, disable index executions " +
+	"to sync blocks and txs without executions")
+
+//newPipeline returns pipeline of blocks for explorer. When executions are indexed and node does not
+//serve them the update fails, so blocks are never saved without executions that are expected for them
+func (e *Explorer) newPipeline() (*fetchPipeline, error) {
+	pipeline := newFetchPipeline(e.BCAdapter, e.Config.App, blocksPageSize)
+	if !pipeline.indexExecutions {
+		return pipeline, nil
+	}
+
+	supported, err := e.BCAdapter.SupportsTxExecutions()
+	if err != nil {
+		return nil, fmt.Errorf("error on checking tx executions of node: %w", err)
+	}
+	if !supported {
+		return nil, errNoTxExecutions
+	}
+	return pipeline, nil
+}
+
+//checkTxExecutions returns an error when executions of txs will not be indexed. Names, permission changes,
+//validator power and balance changes of txs are only derived from txs whose execution is known
+func (e *Explorer) checkTxExecutions() error {
+	if !e.Config.App.IndexExecutions {
+		return fmt.Errorf("index executions is disabled, executions, contracts, logs, names, permission changes, " +
+			"validator power and balance changes of txs are not derived until it is enabled")
+	}
+
+	supported, err := e.BCAdapter.SupportsTxExecutions()
+	if err != nil {
+		return fmt.Errorf("error on checking tx executions of node: %w", err)
+	}
+	if !supported {
+		return errNoTxExecutions
+	}
+	return nil
+}
+
+//numPages returns number of pages in range of heights
+func (p *fetchPipeline) numPages(from uint64, to uint64) int {
+	return int((to-from)/p.pageSize) + 1
+}
+
+//run fetches all blocks from..to and calls write for each page in height order.
+//It stops on first fetching or writing error and returns it
+func (p *fetchPipeline) run(from uint64, to uint64, write func(page *blockPage) error) error {
+	nPages := p.numPages(from, to)
+
+	jobs := make(chan *blockPage)
+	results := make(chan *blockPage, p.depth)
+	quit := make(chan struct{})
+	defer close(quit)
+
+	//slots limits number of pages that are fetched but not written yet
+	slots := make(chan struct{}, p.depth)
+	//txSlots limits number of concurrent requests for block txs and commits
+	txSlots := make(chan struct{}, p.workers)
+
+	go func() {
+		defer close(jobs)
+		for i := 0; i < nPages; i++ {
+			select {
+			case slots <- struct{}{}:
+			case <-quit:
+				return
+			}
+
+			start := from + uint64(i)*p.pageSize
+			end := start + p.pageSize - 1
+			if end > to {
+				end = to
+			}
+
+			select {
+			case jobs <- &blockPage{index: i, from: start, to: end}:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < p.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				p.fetchPage(page, txSlots)
+				select {
+				case results <- page:
+				case <-quit:
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]*blockPage)
+	next := 0
+	for next < nPages {
+		page, ok := <-results
+		if !ok {
+			return fmt.Errorf("fetching blocks %d to %d stopped unexpectedly", from, to)
+		}
+		pending[page.index] = page
+
+		for pending[next] != nil {
+			page := pending[next]
+			delete(pending, next)
+
+			if page.err != nil {
+				return page.err
+			}
+			if err := write(page); err != nil {
+				return err
+			}
+
+			<-slots
+			next++
+		}
+	}
+
+	return nil
+}
+
+//fetchContracts returns contracts that are deployed by txs of a block with hash of their code.
+//Code of a contract that does not exist anymore can not be read, so its code hash is left empty
+func (p *fetchPipeline) fetchContracts(txs []bc.Transaction, execs []bc.TxExecution) []bc.Contract {
+	contracts := bc.DeployedContracts(txs, execs)
+	for i := range contracts {
+		acc, err := p.bcAdapter.GetAccount(contracts[i].Address)
+		if err != nil {
+			println("error on reading code of contract " + contracts[i].Address + ": " + err.Error())
+			continue
+		}
+		contracts[i].CodeHash = bc.CodeHash(acc.Code)
+	}
+	return contracts
+}
+
+//fetchPage fetches blocks of page and transactions, executions, contracts and commit signers of each block in parallel
+func (p *fetchPipeline) fetchPage(page *blockPage, txSlots chan struct{}) {
+	blocks, err := p.bcAdapter.GetBlocks(page.from, page.to)
+	if err != nil {
+		page.err = fmt.Errorf("error on get blocks %d to %d: %s", page.from, page.to, err.Error())
+		return
+	}
+	if uint64(len(blocks)) != page.to-page.from+1 {
+		page.err = fmt.Errorf("blockchain returned %d blocks for range %d to %d", len(blocks), page.from, page.to)
+		return
+	}
+
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Height < blocks[j].Height })
+	page.blocks = blocks
+	page.txs = make(map[int64][]bc.Transaction)
+	page.executions = make(map[int64][]bc.TxExecution)
+	page.contracts = make(map[int64][]bc.Contract)
+	page.hasExecutions = p.indexExecutions
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	signers := make(map[int64][]string)
+	commitSizes := make(map[int64]int)
+	if p.trackSignatures {
+		for _, block := range blocks {
+			wg.Add(1)
+			txSlots <- struct{}{}
+			go func(height int64) {
+				defer wg.Done()
+				defer func() { <-txSlots }()
+
+				inf, errInfo := p.bcAdapter.GetBlockInfo(uint64(height))
+
+				mtx.Lock()
+				defer mtx.Unlock()
+				if errInfo != nil {
+					if page.err == nil {
+						page.err = fmt.Errorf("error on get commit of block %d: %s", height, errInfo.Error())
+					}
+					return
+				}
+				signers[height] = inf.LastCommitSigners
+				commitSizes[height] = inf.LastCommitSize
+			}(block.Height)
+		}
+	}
+
+	for _, block := range blocks {
+		if block.NumTxs <= 0 {
+			continue
+		}
+
+		wg.Add(1)
+		txSlots <- struct{}{}
+		go func(height int64) {
+			defer wg.Done()
+			defer func() { <-txSlots }()
+
+			txs, errTXs := p.bcAdapter.GetTXs(uint64(height))
+
+			var execs []bc.TxExecution
+			var errExecs error
+			if errTXs == nil && p.indexExecutions {
+				execs, errExecs = p.bcAdapter.GetTxExecutions(uint64(height))
+			}
+
+			var contracts []bc.Contract
+			if errTXs == nil && errExecs == nil {
+				contracts = p.fetchContracts(txs, execs)
+			}
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if errTXs != nil {
+				if page.err == nil {
+					page.err = fmt.Errorf("error on get txs of block %d: %s", height, errTXs.Error())
+				}
+				return
+			}
+			if errExecs != nil {
+				if page.err == nil {
+					page.err = fmt.Errorf("error on get tx executions of block %d: %s", height, errExecs.Error())
+				}
+				return
+			}
+			page.txs[height] = txs
+			if p.indexExecutions {
+				page.executions[height] = execs
+			}
+			if len(contracts) > 0 {
+				page.contracts[height] = contracts
+			}
+		}(block.Height)
+	}
+	wg.Wait()
+
+	if p.trackSignatures {
+		for i := range blocks {
+			blocks[i].LastCommitSigners = signers[blocks[i].Height]
+			blocks[i].LastCommitSize = commitSizes[blocks[i].Height]
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package explorer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	config "github.com/BurrowBlocks/config"
+	"github.com/stretchr/testify/require"
+)
+
+//fakeChain returns empty blocks, pages that start at lower heights are returned later
+//so workers finish them out of order. Methods that are not overridden are not used
+type fakeChain struct {
+	bc.Adapter
+	to     uint64
+	failAt uint64
+}
+
+func (f *fakeChain) GetBlocks(from uint64, to uint64) ([]bc.BlockInfo, error) {
+	time.Sleep(time.Duration(f.to-from) * time.Millisecond)
+	if f.failAt >= from && f.failAt <= to {
+		return nil, fmt.Errorf("node is down")
+	}
+
+	blocks := make([]bc.BlockInfo, 0)
+	for h := to; h >= from; h-- {
+		blocks = append(blocks, bc.BlockInfo{Height: int64(h)})
+	}
+	return blocks, nil
+}
+
+func newTestPipeline(chain *fakeChain) *fetchPipeline {
+	app := &config.AppConfig{FetchWorkers: 4, FetchQueueDepth: 4}
+	return newFetchPipeline(chain, app, 3)
+}
+
+func TestPipelineWritesPagesInOrder(t *testing.T) {
+	chain := &fakeChain{to: 20}
+	pipeline := newTestPipeline(chain)
+
+	heights := make([]int64, 0)
+	pages := make([]int, 0)
+	err := pipeline.run(1, 20, func(page *blockPage) error {
+		pages = append(pages, page.index)
+		for _, block := range page.blocks {
+			heights = append(heights, block.Height)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, pages)
+	require.Len(t, heights, 20)
+	for i, h := range heights {
+		require.Equal(t, int64(i+1), h)
+	}
+}
+
+func TestPipelineStopsOnFetchError(t *testing.T) {
+	chain := &fakeChain{to: 20, failAt: 8}
+	pipeline := newTestPipeline(chain)
+
+	pages := make([]int, 0)
+	err := pipeline.run(1, 20, func(page *blockPage) error {
+		pages = append(pages, page.index)
+		return nil
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node is down")
+
+	//blocks 7 to 9 are in third page, pages after it are never written
+	require.Equal(t, []int{0, 1}, pages)
+}
+
+func TestPipelineStopsOnWriteError(t *testing.T) {
+	chain := &fakeChain{to: 20}
+	pipeline := newTestPipeline(chain)
+
+	writeErr := errors.New("database is down")
+	pages := make([]int, 0)
+	err := pipeline.run(1, 20, func(page *blockPage) error {
+		pages = append(pages, page.index)
+		if page.index == 1 {
+			return writeErr
+		}
+		return nil
+	})
+	require.Equal(t, writeErr, err)
+	require.Equal(t, []int{0, 1}, pages)
+}
+
+func TestPipelineNumPages(t *testing.T) {
+	pipeline := newTestPipeline(&fakeChain{})
+
+	require.Equal(t, 1, pipeline.numPages(1, 1))
+	require.Equal(t, 1, pipeline.numPages(1, 3))
+	require.Equal(t, 2, pipeline.numPages(1, 4))
+	require.Equal(t, 7, pipeline.numPages(1, 20))
+}
+
+func TestCheckTxExecutions(t *testing.T) {
+	chain := &memChain{height: 3}
+	e := newMemExplorer(chain, newMemDB())
+	e.Config.App.IndexExecutions = true
+	require.NoError(t, e.checkTxExecutions())
+
+	chain.noExecutions = true
+	require.Equal(t, errNoTxExecutions, e.checkTxExecutions())
+
+	//node is not checked when executions are not indexed
+	chain.noExecutions = false
+	e.Config.App.IndexExecutions = false
+	err := e.checkTxExecutions()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "index executions is disabled")
+}
+
+func TestUpdateAllFailsWithoutTxExecutions(t *testing.T) {
+	chain := &memChain{height: 3, noExecutions: true}
+	dbAdapter := newMemDB()
+	e := newMemExplorer(chain, dbAdapter)
+	e.Config.App.IndexExecutions = true
+
+	//blocks are not saved without executions that are expected for them
+	require.Equal(t, errNoTxExecutions, e.UpdateAll())
+	require.Empty(t, dbAdapter.data.blocks)
+
+	chain.noExecutions = false
+	require.NoError(t, e.UpdateAll())
+	require.Len(t, dbAdapter.data.blocks, 3)
+	require.Len(t, dbAdapter.data.executions, 3)
+}
@@ -0,0 +1,21 @@
+package explorer
+
+import (
+	"time"
+)
+
+//refreshRichList recomputes ranks of rich list if rich list interval is passed since last refresh.
+//Ranks are only a view of saved data, so a failure is only logged and they are refreshed next time
+func (e *Explorer) refreshRichList() {
+	interval := time.Duration(e.Config.App.RichListInterval) * time.Millisecond
+	if interval <= 0 || time.Since(e.lastRichListRefresh) < interval {
+		return
+	}
+
+	err := e.DBAdapter.RefreshRichList()
+	if err != nil {
+		println("error on refreshing rich list: " + err.Error())
+		return
+	}
+	e.lastRichListRefresh = time.Now()
+}
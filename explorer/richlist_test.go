@@ -0,0 +1,60 @@
+package explorer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	config "github.com/BurrowBlocks/config"
+	db "github.com/BurrowBlocks/database"
+	"github.com/stretchr/testify/require"
+)
+
+//fakeRichListDB counts refreshes of rich list
+type fakeRichListDB struct {
+	db.Adapter
+	refreshes int
+	err       error
+}
+
+func (f *fakeRichListDB) RefreshRichList() error {
+	f.refreshes++
+	return f.err
+}
+
+func newRichListExplorer(interval int, dbAdapter db.Adapter) *Explorer {
+	return &Explorer{DBAdapter: dbAdapter, Config: &config.Config{App: &config.AppConfig{RichListInterval: interval}}}
+}
+
+func TestRefreshRichListInterval(t *testing.T) {
+	dbAdapter := &fakeRichListDB{}
+	e := newRichListExplorer(60000, dbAdapter)
+
+	//first sync refreshes, later ones wait for interval
+	e.refreshRichList()
+	e.refreshRichList()
+	require.Equal(t, 1, dbAdapter.refreshes)
+
+	e.lastRichListRefresh = time.Now().Add(-time.Minute)
+	e.refreshRichList()
+	require.Equal(t, 2, dbAdapter.refreshes)
+}
+
+func TestRefreshRichListDisabled(t *testing.T) {
+	dbAdapter := &fakeRichListDB{}
+	newRichListExplorer(0, dbAdapter).refreshRichList()
+	require.Equal(t, 0, dbAdapter.refreshes)
+}
+
+func TestRefreshRichListRetriesAfterError(t *testing.T) {
+	dbAdapter := &fakeRichListDB{err: errors.New("database is down")}
+	e := newRichListExplorer(60000, dbAdapter)
+
+	e.refreshRichList()
+	require.True(t, e.lastRichListRefresh.IsZero())
+
+	dbAdapter.err = nil
+	e.refreshRichList()
+	require.Equal(t, 2, dbAdapter.refreshes)
+	require.False(t, e.lastRichListRefresh.IsZero())
+}
@@ -0,0 +1,206 @@
+package rpc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/BurrowBlocks/abi"
+	bc "github.com/BurrowBlocks/blockchain"
+	db "github.com/BurrowBlocks/database"
+	mux "github.com/gorilla/mux"
+)
+
+//maxABISize is max size of an uploaded ABI json in bytes
+const maxABISize = 1 << 20
+
+//txLogsLimit is max number of logs that are returned with tx details
+const txLogsLimit = 1000
+
+//abiRegistry keeps parsed ABIs of contracts that are saved in database
+var abiRegistry *abi.Registry
+
+//decodedLog is a log with its args that are decoded by ABI of its contract
+type decodedLog struct {
+	db.Log
+	Decoded *abi.DecodedEvent
+}
+
+//isAdmin checks admin token of request that is sent as a bearer token,
+//admin requests are rejected if no token is configured
+func isAdmin(r *http.Request) bool {
+	token := configuration.RestfulServer.AdminToken
+	if token == "" {
+		return false
+	}
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) == 1
+}
+
+func uploadABI(w http.ResponseWriter, r *http.Request) {
+
+	address := mux.Vars(r)["address"]
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	if !isAdmin(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		res.ErrorNumber = 1
+		res.ErrorDescription = "admin token is required"
+		res.Result["abi"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	if !abi.IsAddress(address) {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "invalid address"
+		res.Result["abi"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+	address = abi.NormalizeAddress(address)
+
+	data, errRead := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxABISize))
+	if errRead != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't read abi: " + errRead.Error()
+		res.Result["abi"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	parsed, errParse := abi.Parse(data)
+	if errParse != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParse.Error()
+		res.Result["abi"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	errSaveABI := dbAdapter.SaveABI(address, string(data))
+
+	if errSaveABI != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't save abi: " + errSaveABI.Error()
+		res.Result["abi"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+	abiRegistry.Reset(address)
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["address"] = address
+	res.Result["abi"] = abiSummary(parsed)
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getABI(w http.ResponseWriter, r *http.Request) {
+
+	address := mux.Vars(r)["address"]
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	if !abi.IsAddress(address) {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "invalid address"
+		res.Result["abi"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+	address = abi.NormalizeAddress(address)
+
+	data, errGetABI := dbAdapter.GetABI(address)
+
+	if errGetABI != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get abi: " + errGetABI.Error()
+		res.Result["abi"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+	if data == "" {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "not found"
+		res.Result["abi"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["address"] = address
+	res.Result["abi"] = json.RawMessage(data)
+
+	json.NewEncoder(w).Encode(res)
+}
+
+//abiSummary returns signatures of functions and events of ABI by their selectors and topics
+func abiSummary(a *abi.ABI) map[string]interface{} {
+	functions := make(map[string]string)
+	for selector, method := range a.Functions {
+		functions[selector] = method.Signature
+	}
+	events := make(map[string]string)
+	for topic, event := range a.Events {
+		events[topic] = event.Signature
+	}
+	return map[string]interface{}{"functions": functions, "events": events}
+}
+
+//decodeCall decodes data of a contract call by ABI of called contract,
+//it returns nil if tx is not a call or its contract has no ABI
+func decodeCall(tx *bc.Transaction) *abi.DecodedCall {
+	if tx.Type != "CallTx" || tx.To == "" || tx.Data == "" {
+		return nil
+	}
+
+	contract, err := abiRegistry.Get(tx.To)
+	if err != nil {
+		println("error on loading abi of " + tx.To + ": " + err.Error())
+		return nil
+	}
+	if contract == nil {
+		return nil
+	}
+
+	call, err := contract.DecodeCall(tx.Data)
+	if err != nil {
+		return nil
+	}
+	return call
+}
+
+//decodeLogs decodes logs by ABIs of contracts that emitted them,
+//decoded field of logs whose contract has no ABI is nil
+func decodeLogs(logs []db.Log) []decodedLog {
+	decoded := make([]decodedLog, len(logs))
+	for i, l := range logs {
+		decoded[i].Log = l
+
+		contract, err := abiRegistry.Get(l.Address)
+		if err != nil {
+			println("error on loading abi of " + l.Address + ": " + err.Error())
+			continue
+		}
+		if contract == nil {
+			continue
+		}
+
+		if event, err := contract.DecodeLog(l.Topics, l.Data); err == nil {
+			decoded[i].Decoded = event
+		}
+	}
+	return decoded
+}
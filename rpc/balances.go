@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	db "github.com/BurrowBlocks/database"
+	mux "github.com/gorilla/mux"
+)
+
+func getBalanceHistory(w http.ResponseWriter, r *http.Request) {
+
+	address := strings.ToUpper(mux.Vars(r)["address"])
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 1)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	filter, errFilter := balanceHistoryParams(r)
+	if errFilter != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errFilter.Error()
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	filter.Limit = limit
+	if keys != nil {
+		filter.AfterHeight = keys[0]
+	}
+
+	points, errGetHistory := dbAdapter.GetBalanceHistory(address, filter)
+
+	if errGetHistory != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get balance history: " + errGetHistory.Error()
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["history"] = points
+	if uint64(len(points)) == limit {
+		res.NextCursor = encodeCursor(points[len(points)-1].Height)
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+//balanceHistoryParams reads range of heights of balance history from query string of request
+func balanceHistoryParams(r *http.Request) (db.BalanceHistoryFilter, error) {
+	var filter db.BalanceHistoryFilter
+	var err error
+	filter.FromHeight, filter.ToHeight, err = heightRangeParams(r)
+	return filter, err
+}
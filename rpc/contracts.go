@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/BurrowBlocks/abi"
+	db "github.com/BurrowBlocks/database"
+	mux "github.com/gorilla/mux"
+)
+
+func getContracts(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 2)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["contracts"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	filter := db.ContractFilter{
+		Creator: strings.ToUpper(r.URL.Query().Get("creator")),
+		Limit:   limit,
+	}
+	if keys != nil {
+		filter.BeforeHeight, filter.BeforeTxID = keys[0], uint64(keys[1])
+	}
+
+	contracts, errGetContracts := dbAdapter.GetContracts(filter)
+
+	if errGetContracts != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get contracts: " + errGetContracts.Error()
+		res.Result["contracts"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["contracts"] = contracts
+	if uint64(len(contracts)) == limit {
+		last := contracts[len(contracts)-1]
+		res.NextCursor = encodeCursor(last.Height, int64(last.TxID))
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getContract(w http.ResponseWriter, r *http.Request) {
+
+	address := abi.NormalizeAddress(mux.Vars(r)["address"])
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	contract, errGetContract := dbAdapter.GetContract(address)
+
+	if errGetContract != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get contract: " + errGetContract.Error()
+		res.Result["details"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	var summary map[string]interface{}
+	if contract.HasABI {
+		parsed, errGetABI := abiRegistry.Get(address)
+		if errGetABI != nil {
+			res.ErrorNumber = 1
+			res.ErrorDescription = "can't get abi: " + errGetABI.Error()
+			res.Result["details"] = ""
+			json.NewEncoder(w).Encode(res)
+			return
+		}
+		if parsed != nil {
+			summary = abiSummary(parsed)
+		}
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["details"] = contract
+	res.Result["abi"] = summary
+
+	json.NewEncoder(w).Encode(res)
+}
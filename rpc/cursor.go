@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+//defaultPageLimit is number of rows that are returned when limit is not set
+const defaultPageLimit = 25
+
+//maxPageLimit is max number of rows that can be returned in one page
+const maxPageLimit = 100
+
+//encodeCursor makes an opaque cursor from keys of last returned row
+func encodeCursor(keys ...int64) string {
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = strconv.FormatInt(key, 10)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, ":")))
+}
+
+//decodeCursor returns n keys of cursor or nil for an empty cursor
+func decodeCursor(cursor string, n int) ([]int64, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.Split(string(data), ":")
+	if len(parts) != n {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	keys := make([]int64, n)
+	for i, part := range parts {
+		keys[i], err = strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+	}
+	return keys, nil
+}
+
+//pageParams reads cursor keys and limit from query string of request
+func pageParams(r *http.Request, nKeys int) ([]int64, uint64, error) {
+	keys, err := decodeCursor(r.URL.Query().Get("cursor"), nKeys)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	limit := uint64(defaultPageLimit)
+	strLimit := r.URL.Query().Get("limit")
+	if strLimit != "" {
+		limit, err = strconv.ParseUint(strLimit, 10, 64)
+		if err != nil || limit == 0 {
+			return nil, 0, fmt.Errorf("invalid limit")
+		}
+		if limit > maxPageLimit {
+			limit = maxPageLimit
+		}
+	}
+
+	return keys, limit, nil
+}
@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"encoding/base64"
+	"math"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := [][]int64{
+		{0},
+		{42},
+		{-7},
+		{math.MaxInt64},
+		{12, 345},
+		{math.MaxInt64, math.MinInt64},
+		{1, 2, 3},
+	}
+
+	for _, keys := range tests {
+		cursor := encodeCursor(keys...)
+		decoded, err := decodeCursor(cursor, len(keys))
+		require.NoError(t, err)
+		require.Equal(t, keys, decoded)
+	}
+}
+
+func TestDecodeEmptyCursor(t *testing.T) {
+	keys, err := decodeCursor("", 2)
+	require.NoError(t, err)
+	require.Nil(t, keys)
+}
+
+func TestDecodeMalformedCursor(t *testing.T) {
+	raw := func(str string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(str))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		n      int
+	}{
+		{"not base64", "!!!", 1},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("1")), 1},
+		{"fewer keys", raw("12"), 2},
+		{"more keys", raw("12:34:56"), 2},
+		{"empty key", raw("12:"), 2},
+		{"not a number", raw("12:abc"), 2},
+		{"out of range", raw("99999999999999999999"), 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeCursor(test.cursor, test.n)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPageParams(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v2/txs", nil)
+	keys, limit, err := pageParams(r, 2)
+	require.NoError(t, err)
+	require.Nil(t, keys)
+	require.Equal(t, uint64(defaultPageLimit), limit)
+
+	r = httptest.NewRequest("GET", "/api/v2/txs?limit=1000&cursor="+encodeCursor(5, 9), nil)
+	keys, limit, err = pageParams(r, 2)
+	require.NoError(t, err)
+	require.Equal(t, []int64{5, 9}, keys)
+	require.Equal(t, uint64(maxPageLimit), limit)
+
+	for _, query := range []string{"limit=0", "limit=-1", "limit=ten", "cursor=" + encodeCursor(5)} {
+		r = httptest.NewRequest("GET", "/api/v2/txs?"+query, nil)
+		_, _, err = pageParams(r, 2)
+		require.Error(t, err, query)
+	}
+}
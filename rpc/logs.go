@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	db "github.com/BurrowBlocks/database"
+)
+
+func getLogs(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 2)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["logs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	filter, errFilter := logFilterParams(r)
+	if errFilter != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errFilter.Error()
+		res.Result["logs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	filter.Limit = limit
+	if keys != nil {
+		filter.AfterHeight, filter.AfterIndex = keys[0], keys[1]
+	}
+
+	logs, errGetLogs := dbAdapter.GetLogs(filter)
+
+	if errGetLogs != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get logs: " + errGetLogs.Error()
+		res.Result["logs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["logs"] = decodeLogs(logs)
+	if uint64(len(logs)) == limit {
+		last := logs[len(logs)-1]
+		res.NextCursor = encodeCursor(last.Height, last.LogIndex)
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+//logFilterParams reads log filters from query string of request. Address and topics
+//can be comma separated lists, a log matches a list if it matches any of its items
+func logFilterParams(r *http.Request) (db.LogFilter, error) {
+	var filter db.LogFilter
+	var err error
+	query := r.URL.Query()
+
+	filter.Addresses = listParam(query.Get("address"))
+	for i := range filter.Topics {
+		filter.Topics[i] = listParam(query.Get(fmt.Sprintf("topic%d", i)))
+	}
+
+	if str := query.Get("from_block"); str != "" {
+		if filter.FromHeight, err = strconv.ParseInt(str, 10, 64); err != nil || filter.FromHeight < 0 {
+			return filter, fmt.Errorf("invalid from_block")
+		}
+	}
+	if str := query.Get("to_block"); str != "" {
+		if filter.ToHeight, err = strconv.ParseInt(str, 10, 64); err != nil || filter.ToHeight < 0 {
+			return filter, fmt.Errorf("invalid to_block")
+		}
+	}
+
+	return filter, nil
+}
+
+//listParam splits a comma separated list of hex values, 0x prefixes are removed
+func listParam(str string) []string {
+	if str == "" {
+		return nil
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(item)), "0x")
+		if item != "" {
+			items = append(items, strings.ToUpper(item))
+		}
+	}
+	return items
+}
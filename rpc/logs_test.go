@@ -0,0 +1,33 @@
+package rpc
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListParam(t *testing.T) {
+	require.Nil(t, listParam(""))
+	require.Equal(t, []string{"AB"}, listParam("0xab"))
+	require.Equal(t, []string{"AB", "CD"}, listParam(" 0XaB, ,cd,"))
+	require.Equal(t, []string{}, listParam(",0x"))
+}
+
+func TestLogFilterParams(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v2/logs?address=0xc1,c2&topic0=0xab&topic3=cd&from_block=5&to_block=9", nil)
+	filter, err := logFilterParams(r)
+	require.NoError(t, err)
+	require.Equal(t, []string{"C1", "C2"}, filter.Addresses)
+	require.Equal(t, []string{"AB"}, filter.Topics[0])
+	require.Nil(t, filter.Topics[1])
+	require.Equal(t, []string{"CD"}, filter.Topics[3])
+	require.Equal(t, int64(5), filter.FromHeight)
+	require.Equal(t, int64(9), filter.ToHeight)
+
+	for _, query := range []string{"from_block=-1", "to_block=x"} {
+		r = httptest.NewRequest("GET", "/api/v2/logs?"+query, nil)
+		_, err = logFilterParams(r)
+		require.Error(t, err, query)
+	}
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	mux "github.com/gorilla/mux"
+)
+
+//nameHistoryCount is number of latest entries that are returned with name details
+const nameHistoryCount = 50
+
+func getName(w http.ResponseWriter, r *http.Request) {
+
+	name := mux.Vars(r)["name"]
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	entry, errGetName := dbAdapter.GetName(name)
+	if errGetName == nil && entry == nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "not found"
+		res.Result["details"] = ""
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	if errGetName != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get name: " + errGetName.Error()
+		res.Result["details"] = ""
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	history, errGetHistory := dbAdapter.GetNameHistory(name, nameHistoryCount)
+
+	if errGetHistory != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get name history: " + errGetHistory.Error()
+		res.Result["details"] = ""
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["details"] = entry
+	res.Result["history"] = history
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getAccountNames(w http.ResponseWriter, r *http.Request) {
+
+	address := strings.ToUpper(mux.Vars(r)["address"])
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	names, errGetNames := dbAdapter.GetAccountNames(address)
+
+	if errGetNames != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get names: " + errGetNames.Error()
+		res.Result["names"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["names"] = names
+
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,183 @@
+package rpc
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	bc "github.com/BurrowBlocks/blockchain"
+)
+
+//maxUnindexedBlocks is max number of latest blocks that are searched in node for a tx that is not saved
+const maxUnindexedBlocks = 5
+
+//maxMissedTxs is max number of hashes that are remembered as not found in node
+const maxMissedTxs = 10000
+
+//missedTxs keeps last height of node that each missed hash is searched to, so blocks are not searched again
+var missedTxs = newMissCache(maxMissedTxs)
+
+//missCache keeps heights that hashes are not found up to, it is cleared when it is full
+type missCache struct {
+	size    int
+	mtx     sync.Mutex
+	heights map[string]uint64
+}
+
+//newMissCache creates a cache that keeps at most size hashes
+func newMissCache(size int) *missCache {
+	return &missCache{
+		size:    size,
+		heights: make(map[string]uint64),
+	}
+}
+
+//from returns first height that should be searched for hash, blocks up to a missed height are skipped
+func (c *missCache) from(hash string, from uint64) uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if height, ok := c.heights[hash]; ok && height >= from {
+		return height + 1
+	}
+	return from
+}
+
+//miss saves that hash is not found in blocks up to height
+func (c *missCache) miss(hash string, height uint64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, ok := c.heights[hash]; !ok && len(c.heights) >= c.size {
+		c.heights = make(map[string]uint64)
+	}
+	c.heights[hash] = height
+}
+
+//txLookup keeps whether node serves lookup of txs by hash once it is known
+var txLookup = &lookupSupport{}
+
+//lookupSupport checks once whether node serves lookup of txs by hash, a check that fails is done again
+type lookupSupport struct {
+	mtx       sync.Mutex
+	known     bool
+	supported bool
+}
+
+//isSupported returns whether node serves lookup of txs by hash, it is checked by check if it is not known
+func (s *lookupSupport) isSupported(check func() (bool, error)) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.known {
+		return s.supported, nil
+	}
+	supported, err := check()
+	if err != nil {
+		return false, err
+	}
+	if !supported {
+		println("node does not serve /tx?hash=, txs that are not saved are only searched in latest blocks of node")
+	}
+	s.known, s.supported = true, supported
+	return supported, nil
+}
+
+//unsupported saves that node does not serve lookup of txs by hash
+func (s *lookupSupport) unsupported() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.known, s.supported = true, false
+}
+
+//errInvalidHash is returned for tx hashes that are not 32 bytes of hex
+var errInvalidHash = fmt.Errorf("invalid hash")
+
+//lookupTx returns a saved tx with time of its block, a tx that is not saved yet is read from node.
+//Node is only asked when tx is not in database, it returns whether tx is saved
+func lookupTx(hash string) (*bc.Transaction, string, bool, error) {
+	hashBytes, err := hex.DecodeString(hash)
+	if err != nil || len(hashBytes) != 32 {
+		return nil, "", false, errInvalidHash
+	}
+
+	tx, txtime, err := dbAdapter.GetTx(hash)
+	if err == nil {
+		return tx, txtime, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, "", false, err
+	}
+
+	//tx may be in a block that is not saved yet
+	tx, txtime, err = findUnindexedTx(hashBytes)
+	return tx, txtime, false, err
+}
+
+//findUnindexedTx finds tx with given hash in node, latest blocks that are not saved in database
+//yet are searched if node can not find a tx by hash. Stock nodes do not serve lookup by hash, so
+//only latest blocks are searched on them. Searched blocks are not searched again for the same hash.
+//It returns tx with time of its block
+func findUnindexedTx(hash []byte) (*bc.Transaction, string, error) {
+	lookup, err := txLookup.isSupported(bcAdapter.SupportsTxLookup)
+	if err != nil {
+		return nil, "", err
+	}
+	if lookup {
+		tx, errLookup := bcAdapter.GetTxByHash(hash)
+		if errLookup == nil {
+			inf, errInfo := bcAdapter.GetBlockInfo(uint64(tx.BlockID))
+			if errInfo != nil {
+				return nil, "", errInfo
+			}
+			return tx, inf.Time, nil
+		}
+		if errors.Is(errLookup, bc.ErrTxLookupNotServed) {
+			txLookup.unsupported()
+		}
+	}
+
+	from := uint64(1)
+	state, err := dbAdapter.GetSyncState()
+	if err != nil {
+		return nil, "", err
+	}
+	if state != nil {
+		from = state.LastHeight + 1
+	}
+
+	last, err := bcAdapter.GetBlocksLastHeight()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := hex.EncodeToString(hash)
+	from = missedTxs.from(key, from)
+	if from > last {
+		return nil, "", fmt.Errorf("not found")
+	}
+	if last-from+1 > maxUnindexedBlocks {
+		from = last - maxUnindexedBlocks + 1
+	}
+
+	blocks, err := bcAdapter.GetBlocks(from, last)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if blocks[i].NumTxs <= 0 {
+			continue
+		}
+
+		tx, errGetTx := bcAdapter.GetTx(uint64(blocks[i].Height), hash)
+		if errGetTx == nil {
+			return tx, blocks[i].Time, nil
+		}
+	}
+
+	missedTxs.miss(key, last)
+	return nil, "", fmt.Errorf("not found")
+}
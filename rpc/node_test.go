@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissCache(t *testing.T) {
+	c := newMissCache(2)
+	require.Equal(t, uint64(10), c.from("A", 10))
+
+	//blocks up to 15 are searched, so only later blocks are searched again
+	c.miss("A", 15)
+	require.Equal(t, uint64(16), c.from("A", 10))
+	require.Equal(t, uint64(20), c.from("A", 20))
+	require.Equal(t, uint64(10), c.from("B", 10))
+
+	c.miss("A", 18)
+	require.Equal(t, uint64(19), c.from("A", 10))
+
+	//cache is cleared when it is full
+	c.miss("B", 12)
+	c.miss("C", 12)
+	require.Equal(t, uint64(10), c.from("A", 10))
+	require.Equal(t, uint64(10), c.from("B", 10))
+	require.Equal(t, uint64(13), c.from("C", 10))
+}
+
+func TestLookupSupport(t *testing.T) {
+	checks := 0
+	check := func(supported bool, err error) func() (bool, error) {
+		return func() (bool, error) {
+			checks++
+			return supported, err
+		}
+	}
+
+	//a check that fails is done again
+	s := &lookupSupport{}
+	_, err := s.isSupported(check(false, errors.New("node is down")))
+	require.Error(t, err)
+	supported, err := s.isSupported(check(true, nil))
+	require.NoError(t, err)
+	require.True(t, supported)
+	require.Equal(t, 2, checks)
+
+	//support is only checked once it is known
+	supported, err = s.isSupported(check(false, nil))
+	require.NoError(t, err)
+	require.True(t, supported)
+	require.Equal(t, 2, checks)
+
+	//a node that replies it does not have the route is not asked again
+	s.unsupported()
+	supported, err = s.isSupported(check(true, nil))
+	require.NoError(t, err)
+	require.False(t, supported)
+	require.Equal(t, 2, checks)
+}
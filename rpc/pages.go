@@ -0,0 +1,232 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/BurrowBlocks/database"
+	mux "github.com/gorilla/mux"
+)
+
+func getAccountsPage(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 1)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["accs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	afterID := uint64(0)
+	if keys != nil {
+		afterID = uint64(keys[0])
+	}
+
+	accs, errGetAccounts := dbAdapter.GetAccountsPage(afterID, limit)
+
+	if errGetAccounts != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get accounts: " + errGetAccounts.Error()
+		res.Result["accs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["accs"] = accs
+	if uint64(len(accs)) == limit {
+		last := accs[len(accs)-1]
+		res.NextCursor = encodeCursor(int64(last.ID))
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getAccountTxsPage(w http.ResponseWriter, r *http.Request) {
+
+	address := mux.Vars(r)["address"]
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 2)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["txs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	afterBlockID, afterID := int64(0), uint64(0)
+	if keys != nil {
+		afterBlockID, afterID = keys[0], uint64(keys[1])
+	}
+
+	txs, errGetAccountTxs := dbAdapter.GetAccountTxsPage(address, afterBlockID, afterID, limit)
+
+	if errGetAccountTxs != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get account txs: " + errGetAccountTxs.Error()
+		res.Result["txs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["txs"] = txs
+	if uint64(len(txs)) == limit {
+		last := txs[len(txs)-1]
+		res.NextCursor = encodeCursor(last.BlockID, int64(last.ID))
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getLatestTxsPage(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 2)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["txs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	//block id and id of txs are integer columns
+	beforeBlockID, beforeID := int64(math.MaxInt32), uint64(math.MaxInt32)
+	if keys != nil {
+		beforeBlockID, beforeID = keys[0], uint64(keys[1])
+	}
+
+	txs, errGetLatestTxs := dbAdapter.GetLatestTxsPage(beforeBlockID, beforeID, limit)
+
+	if errGetLatestTxs != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get latest txs: " + errGetLatestTxs.Error()
+		res.Result["txs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["txs"] = txs
+	if uint64(len(txs)) == limit {
+		last := txs[len(txs)-1]
+		res.NextCursor = encodeCursor(last.BlockID, int64(last.ID))
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getBlocksPage(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 1)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["blocks"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	filter, errFilter := blockFilterParams(r)
+	if errFilter != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errFilter.Error()
+		res.Result["blocks"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	filter.Limit = limit
+	if keys != nil {
+		filter.AfterHeight = keys[0]
+	}
+
+	blocks, errGetBlocks := dbAdapter.GetBlocksList(filter)
+
+	if errGetBlocks != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get blocks: " + errGetBlocks.Error()
+		res.Result["blocks"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["blocks"] = blocks
+	if uint64(len(blocks)) == limit {
+		last := blocks[len(blocks)-1]
+		res.NextCursor = encodeCursor(last.Height)
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+//blockFilterParams reads block filters from query string of request, all of them are optional
+func blockFilterParams(r *http.Request) (db.BlockFilter, error) {
+	var filter db.BlockFilter
+	var err error
+	query := r.URL.Query()
+
+	if str := query.Get("min_height"); str != "" {
+		if filter.MinHeight, err = strconv.ParseInt(str, 10, 64); err != nil || filter.MinHeight < 0 {
+			return filter, fmt.Errorf("invalid min_height")
+		}
+	}
+	if str := query.Get("max_height"); str != "" {
+		if filter.MaxHeight, err = strconv.ParseInt(str, 10, 64); err != nil || filter.MaxHeight < 0 {
+			return filter, fmt.Errorf("invalid max_height")
+		}
+	}
+	if str := query.Get("from_time"); str != "" {
+		if filter.FromTime, err = time.Parse(time.RFC3339, str); err != nil {
+			return filter, fmt.Errorf("invalid from_time, it should be in RFC3339 format")
+		}
+	}
+	if str := query.Get("to_time"); str != "" {
+		if filter.ToTime, err = time.Parse(time.RFC3339, str); err != nil {
+			return filter, fmt.Errorf("invalid to_time, it should be in RFC3339 format")
+		}
+	}
+	if str := query.Get("with_txs"); str != "" {
+		if filter.OnlyWithTxs, err = strconv.ParseBool(str); err != nil {
+			return filter, fmt.Errorf("invalid with_txs")
+		}
+	}
+
+	switch query.Get("sort") {
+	case "", "desc":
+		filter.Ascending = false
+	case "asc":
+		filter.Ascending = true
+	default:
+		return filter, fmt.Errorf("invalid sort, it should be asc or desc")
+	}
+
+	filter.Proposer = strings.ToUpper(query.Get("proposer"))
+
+	return filter, nil
+}
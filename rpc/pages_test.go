@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	db "github.com/BurrowBlocks/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockFilterParams(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v2/blocks", nil)
+	filter, err := blockFilterParams(r)
+	require.NoError(t, err)
+	require.Equal(t, db.BlockFilter{}, filter)
+
+	r = httptest.NewRequest("GET", "/api/v2/blocks?min_height=5&max_height=50&from_time=2020-01-01T00:00:00Z"+
+		"&to_time=2020-01-02T00:00:00Z&with_txs=true&sort=asc&proposer=v1", nil)
+	filter, err = blockFilterParams(r)
+	require.NoError(t, err)
+	require.Equal(t, db.BlockFilter{MinHeight: 5, MaxHeight: 50,
+		FromTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), ToTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		Proposer: "V1", OnlyWithTxs: true, Ascending: true}, filter)
+
+	for _, query := range []string{"min_height=-1", "max_height=x", "from_time=2020-01-01", "to_time=now",
+		"with_txs=maybe", "sort=up"} {
+		r = httptest.NewRequest("GET", "/api/v2/blocks?"+query, nil)
+		_, err = blockFilterParams(r)
+		require.Error(t, err, query)
+	}
+}
+
+func TestTxIDIsNotEncoded(t *testing.T) {
+	//v1 responses keep fields that they had before ids were used for cursors
+	res := Response{Result: map[string]interface{}{"txs": []bc.Transaction{{ID: 7, Hash: "T1"}}}}
+	out, err := json.Marshal(res)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `"Hash":"T1"`)
+	require.NotContains(t, string(out), `"ID"`)
+}
+
+func TestGetLatestTxsPageWithoutCursor(t *testing.T) {
+	obe := connectTestDB(t, "rpc_latest_txs_test")
+	defer obe.Disconnect()
+	dbAdapter = obe
+
+	require.NoError(t, obe.InsertBlocksBulk([]bc.BlockInfo{{ChainID: "C1", Height: 1, BlockHash: "H1",
+		Time: "2020-01-01T00:00:00Z", NumTxs: 2}}))
+	require.NoError(t, obe.InsertTxsBulk([]bc.Transaction{
+		{Type: "SendTx", BlockID: 1, Hash: "T1", From: "A1", To: "B1"},
+		{Type: "SendTx", BlockID: 1, Hash: "T2", From: "A1", To: "B1"},
+	}))
+
+	w := httptest.NewRecorder()
+	getLatestTxsPage(w, httptest.NewRequest("GET", "/api/v2/txs?limit=1", nil))
+
+	var res struct {
+		ErrorNumber int    `json:"error"`
+		NextCursor  string `json:"next_cursor"`
+		Result      struct {
+			Txs []bc.Transaction `json:"txs"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+	require.Equal(t, 0, res.ErrorNumber)
+	require.Len(t, res.Result.Txs, 1)
+	require.Equal(t, "T2", res.Result.Txs[0].Hash)
+	require.NotEmpty(t, res.NextCursor)
+}
@@ -0,0 +1,58 @@
+package rpc
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	mux "github.com/gorilla/mux"
+)
+
+//permissionChangesCount is number of latest permission changes that are returned with permissions of account
+const permissionChangesCount = 100
+
+func getAccountPermissions(w http.ResponseWriter, r *http.Request) {
+
+	address := strings.ToUpper(mux.Vars(r)["address"])
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	//accounts that are not saved yet only have history
+	var perms *bc.Permissions
+	acc, errGetAccount := dbAdapter.GetAccountByAddress(address)
+	if errGetAccount == nil {
+		perms, errGetAccount = bc.ParsePermissions(acc.Permission)
+	} else if errGetAccount == sql.ErrNoRows {
+		errGetAccount = nil
+	}
+
+	if errGetAccount != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get permissions: " + errGetAccount.Error()
+		res.Result["permissions"] = ""
+		res.Result["changes"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	changes, errGetChanges := dbAdapter.GetPermissionChanges(address, permissionChangesCount)
+
+	if errGetChanges != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get permission changes: " + errGetChanges.Error()
+		res.Result["permissions"] = ""
+		res.Result["changes"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["permissions"] = perms
+	res.Result["changes"] = changes
+
+	json.NewEncoder(w).Encode(res)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/BurrowBlocks/abi"
 	bc "github.com/BurrowBlocks/blockchain"
 	config "github.com/BurrowBlocks/config"
 	db "github.com/BurrowBlocks/database"
@@ -22,6 +23,7 @@ type Response struct {
 	ErrorNumber      int                    `json:"error"`
 	ErrorDescription string                 `json:"desc"`
 	Result           map[string]interface{} `json:"result"`
+	NextCursor       string                 `json:"next_cursor,omitempty"`
 }
 
 //InitServer for init restful API Server
@@ -30,6 +32,7 @@ func InitServer(configObject *config.Config, dbObject *db.Postgre, bcObject *bc.
 	configuration = configObject
 	dbAdapter = dbObject
 	bcAdapter = bcObject
+	abiRegistry = abi.NewRegistry(dbAdapter.GetABI)
 
 	router := mux.NewRouter().StrictSlash(true)
 
@@ -48,6 +51,27 @@ func InitServer(configObject *config.Config, dbObject *db.Postgre, bcObject *bc.
 	router.HandleFunc("/api/v1/txscount", getTxsCount).Methods("GET")
 	router.HandleFunc("/api/v1/latesttxs/{count}", getLatestTxs).Methods("GET")
 
+	router.HandleFunc("/api/v2/accounts", getAccountsPage).Methods("GET")
+	router.HandleFunc("/api/v2/accounts/top", getRichList).Methods("GET")
+	router.HandleFunc("/api/v2/accounts/{address}/txs", getAccountTxsPage).Methods("GET")
+	router.HandleFunc("/api/v2/accounts/{address}/balance-history", getBalanceHistory).Methods("GET")
+	router.HandleFunc("/api/v2/accounts/{address}/names", getAccountNames).Methods("GET")
+	router.HandleFunc("/api/v2/accounts/{address}/permissions", getAccountPermissions).Methods("GET")
+	router.HandleFunc("/api/v2/txs", getLatestTxsPage).Methods("GET")
+	router.HandleFunc("/api/v2/txs/{hash}", getTxDetails).Methods("GET")
+	router.HandleFunc("/api/v2/blocks", getBlocksPage).Methods("GET")
+	router.HandleFunc("/api/v2/logs", getLogs).Methods("GET")
+	router.HandleFunc("/api/v2/names/{name}", getName).Methods("GET")
+	router.HandleFunc("/api/v2/contracts", getContracts).Methods("GET")
+	router.HandleFunc("/api/v2/contracts/{address}", getContract).Methods("GET")
+	router.HandleFunc("/api/v2/contracts/{address}/abi", getABI).Methods("GET")
+	router.HandleFunc("/api/v2/contracts/{address}/abi", uploadABI).Methods("POST")
+	router.HandleFunc("/api/v2/validators", getValidators).Methods("GET")
+	router.HandleFunc("/api/v2/validators/liveness", getValidatorsLiveness).Methods("GET")
+	router.HandleFunc("/api/v2/validators/power-history", getVotingPowerHistory).Methods("GET")
+	router.HandleFunc("/api/v2/validators/{address}", getValidator).Methods("GET")
+	router.HandleFunc("/api/v2/validators/{address}/liveness", getValidatorLiveness).Methods("GET")
+
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodOptions, http.MethodPut, http.MethodDelete},
@@ -73,7 +97,7 @@ func getHash(w http.ResponseWriter, r *http.Request) {
 	var res Response
 	res.Result = make(map[string]interface{})
 
-	tx, txtime, errGetTx := dbAdapter.GetTx(hash)
+	tx, txtime, indexed, errGetTx := lookupTx(hash)
 	if errGetTx != nil {
 		res.ErrorNumber = 1
 		res.ErrorDescription = "not found"
@@ -87,6 +111,7 @@ func getHash(w http.ResponseWriter, r *http.Request) {
 	res.ErrorDescription = "ok"
 	res.Result["height"] = strconv.FormatInt(tx.BlockID, 10)
 	res.Result["time"] = txtime
+	res.Result["indexed"] = indexed
 
 	json.NewEncoder(w).Encode(res)
 }
@@ -150,7 +175,13 @@ func getBlock(w http.ResponseWriter, r *http.Request) {
 	var res Response
 	res.Result = make(map[string]interface{})
 
+	indexed := true
 	block, errGetBlock := dbAdapter.GetBlock(id)
+	if errGetBlock != nil && id > 0 {
+		//block may not be saved yet
+		indexed = false
+		block, errGetBlock = bcAdapter.GetBlock(uint64(id))
+	}
 
 	if errGetBlock != nil {
 		res.ErrorNumber = 1
@@ -163,6 +194,7 @@ func getBlock(w http.ResponseWriter, r *http.Request) {
 	res.ErrorNumber = 0
 	res.ErrorDescription = "ok"
 	res.Result["details"] = block
+	res.Result["indexed"] = indexed
 
 	json.NewEncoder(w).Encode(res)
 }
@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+
+	db "github.com/BurrowBlocks/database"
+)
+
+func getRichList(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 1)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["accs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	order := r.URL.Query().Get("order")
+	switch order {
+	case "":
+		order = db.RichListByBalance
+	case db.RichListByBalance, db.RichListByTxs, db.RichListByVolume:
+	default:
+		res.ErrorNumber = 1
+		res.ErrorDescription = "invalid order, it should be balance, txs or volume"
+		res.Result["accs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	afterRank := uint64(0)
+	if keys != nil {
+		afterRank = uint64(keys[0])
+	}
+
+	accs, errGetRichList := dbAdapter.GetRichList(order, afterRank, limit)
+
+	if errGetRichList != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get rich list: " + errGetRichList.Error()
+		res.Result["accs"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["order"] = order
+	res.Result["accs"] = accs
+	if uint64(len(accs)) == limit {
+		res.NextCursor = encodeCursor(int64(accs[len(accs)-1].Rank))
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
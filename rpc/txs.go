@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	db "github.com/BurrowBlocks/database"
+	mux "github.com/gorilla/mux"
+)
+
+func getTxDetails(w http.ResponseWriter, r *http.Request) {
+
+	hash := mux.Vars(r)["hash"]
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	tx, txtime, indexed, errGetTx := lookupTx(hash)
+	if errGetTx == nil && indexed {
+		tx.Inputs, tx.Outputs, errGetTx = dbAdapter.GetTxInputsOutputs(hash)
+	}
+
+	if errGetTx != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get tx: " + errGetTx.Error()
+		res.Result["details"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	var execution *bc.TxExecution
+	logs := make([]db.Log, 0)
+	if indexed {
+		var errGetExecution error
+		execution, errGetExecution = dbAdapter.GetTxExecution(hash)
+		if errGetExecution != nil {
+			res.ErrorNumber = 1
+			res.ErrorDescription = "can't get tx execution: " + errGetExecution.Error()
+			res.Result["details"] = ""
+			json.NewEncoder(w).Encode(res)
+			return
+		}
+
+		var errGetLogs error
+		logs, errGetLogs = dbAdapter.GetLogs(db.LogFilter{TxHash: tx.Hash, Limit: txLogsLimit})
+		if errGetLogs != nil {
+			res.ErrorNumber = 1
+			res.ErrorDescription = "can't get tx logs: " + errGetLogs.Error()
+			res.Result["details"] = ""
+			json.NewEncoder(w).Encode(res)
+			return
+		}
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["details"] = tx
+	res.Result["time"] = txtime
+	res.Result["indexed"] = indexed
+	res.Result["execution"] = execution
+	res.Result["decoded"] = decodeCall(tx)
+	res.Result["logs"] = decodeLogs(logs)
+
+	json.NewEncoder(w).Encode(res)
+}
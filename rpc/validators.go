@@ -0,0 +1,265 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	db "github.com/BurrowBlocks/database"
+	mux "github.com/gorilla/mux"
+)
+
+//validatorChangesCount is number of latest set changes that are returned with validator details
+const validatorChangesCount = 50
+
+//defaultLivenessWindow is number of latest heights that liveness is checked in when window is not set
+const defaultLivenessWindow = 100
+
+//maxLivenessWindow is max number of heights that liveness can be checked in
+const maxLivenessWindow = 10000
+
+//signaturesTracked returns whether commit signatures are saved while syncing, uptime and liveness of
+//validators are empty when they are not
+func signaturesTracked() bool {
+	return configuration != nil && configuration.App != nil && configuration.App.TrackSignatures
+}
+
+func getValidators(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	validators, errGetValidators := dbAdapter.GetValidators()
+
+	if errGetValidators != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get validators: " + errGetValidators.Error()
+		res.Result["validators"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["validators"] = validators
+	res.Result["signatures_tracked"] = signaturesTracked()
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getValidator(w http.ResponseWriter, r *http.Request) {
+
+	address := strings.ToUpper(mux.Vars(r)["address"])
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	validator, errGetValidator := dbAdapter.GetValidator(address)
+
+	if errGetValidator != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get validator: " + errGetValidator.Error()
+		res.Result["details"] = ""
+		res.Result["changes"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	changes, errGetChanges := dbAdapter.GetValidatorSetChanges(address, validatorChangesCount)
+
+	if errGetChanges != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get validator set changes: " + errGetChanges.Error()
+		res.Result["details"] = ""
+		res.Result["changes"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	powerChanges, errGetPowerChanges := dbAdapter.GetValidatorPowerChanges(address, validatorChangesCount)
+
+	if errGetPowerChanges != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get validator power changes: " + errGetPowerChanges.Error()
+		res.Result["details"] = ""
+		res.Result["changes"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["details"] = validator
+	res.Result["changes"] = changes
+	res.Result["power_changes"] = powerChanges
+	res.Result["signatures_tracked"] = signaturesTracked()
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getValidatorsLiveness(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	window, errWindow := livenessWindow(r)
+	if errWindow != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errWindow.Error()
+		res.Result["validators"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	liveness, errGetLiveness := dbAdapter.GetValidatorsLiveness(window)
+
+	if errGetLiveness != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get validators liveness: " + errGetLiveness.Error()
+		res.Result["validators"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["window"] = window
+	res.Result["validators"] = liveness
+	res.Result["signatures_tracked"] = signaturesTracked()
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getValidatorLiveness(w http.ResponseWriter, r *http.Request) {
+
+	address := strings.ToUpper(mux.Vars(r)["address"])
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	window, errWindow := livenessWindow(r)
+	if errWindow != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errWindow.Error()
+		res.Result["timeline"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	signatures, errGetSignatures := dbAdapter.GetValidatorSignatures(address, window)
+
+	if errGetSignatures != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get validator signatures: " + errGetSignatures.Error()
+		res.Result["timeline"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	signed, missed := 0, 0
+	for _, s := range signatures {
+		if s.Signed {
+			signed++
+		} else {
+			missed++
+		}
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["window"] = window
+	res.Result["signed"] = signed
+	res.Result["missed"] = missed
+	res.Result["timeline"] = signatures
+	res.Result["signatures_tracked"] = signaturesTracked()
+
+	json.NewEncoder(w).Encode(res)
+}
+
+func getVotingPowerHistory(w http.ResponseWriter, r *http.Request) {
+
+	var res Response
+	res.Result = make(map[string]interface{})
+
+	keys, limit, errParams := pageParams(r, 1)
+	if errParams != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errParams.Error()
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	var filter db.VotingPowerFilter
+	var errRange error
+	filter.FromHeight, filter.ToHeight, errRange = heightRangeParams(r)
+	if errRange != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = errRange.Error()
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	filter.Limit = limit
+	if keys != nil {
+		filter.AfterHeight = keys[0]
+	}
+
+	history, errGetHistory := dbAdapter.GetVotingPowerHistory(filter)
+
+	if errGetHistory != nil {
+		res.ErrorNumber = 1
+		res.ErrorDescription = "can't get voting power history: " + errGetHistory.Error()
+		res.Result["history"] = ""
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	res.ErrorNumber = 0
+	res.ErrorDescription = "ok"
+	res.Result["history"] = history
+	if uint64(len(history)) == limit {
+		res.NextCursor = encodeCursor(history[len(history)-1].Height)
+	}
+
+	json.NewEncoder(w).Encode(res)
+}
+
+//heightRangeParams reads from_block and to_block from query string of request, zero means not set
+func heightRangeParams(r *http.Request) (int64, int64, error) {
+	var from, to int64
+	var err error
+	query := r.URL.Query()
+
+	if str := query.Get("from_block"); str != "" {
+		if from, err = strconv.ParseInt(str, 10, 64); err != nil || from < 0 {
+			return 0, 0, fmt.Errorf("invalid from_block")
+		}
+	}
+	if str := query.Get("to_block"); str != "" {
+		if to, err = strconv.ParseInt(str, 10, 64); err != nil || to < 0 {
+			return 0, 0, fmt.Errorf("invalid to_block")
+		}
+	}
+	return from, to, nil
+}
+
+//livenessWindow reads number of latest heights from query string of request
+func livenessWindow(r *http.Request) (uint64, error) {
+	strWindow := r.URL.Query().Get("window")
+	if strWindow == "" {
+		return defaultLivenessWindow, nil
+	}
+
+	window, err := strconv.ParseUint(strWindow, 10, 64)
+	if err != nil || window == 0 {
+		return 0, fmt.Errorf("invalid window")
+	}
+	if window > maxLivenessWindow {
+		window = maxLivenessWindow
+	}
+	return window, nil
+}
@@ -0,0 +1,134 @@
+package rpc
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	bc "github.com/BurrowBlocks/blockchain"
+	config "github.com/BurrowBlocks/config"
+	db "github.com/BurrowBlocks/database"
+	"github.com/stretchr/testify/require"
+)
+
+//testDSNEnv names environment variable with connection string of a postgres server that tests may
+//create and drop databases on, in key=value form. Tests that need postgres are skipped when it is not set
+const testDSNEnv = "BURROWBLOCKS_TEST_DSN"
+
+//connectTestDB creates an empty database on server of testDSNEnv, connects to it and migrates it.
+//Database is dropped when test finishes
+func connectTestDB(t *testing.T, name string) *db.Postgre {
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skip(testDSNEnv + " is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	require.NoError(t, admin.Ping())
+
+	dbName := "burrowblocks_test_" + name
+	_, err = admin.Exec(`DROP DATABASE IF EXISTS "` + dbName + `";`)
+	require.NoError(t, err)
+	_, err = admin.Exec(`CREATE DATABASE "` + dbName + `";`)
+	require.NoError(t, err)
+
+	conn, err := sql.Open("postgres", dsn+" dbname="+dbName)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		admin.Exec(`DROP DATABASE IF EXISTS "` + dbName + `";`)
+		admin.Close()
+	})
+	require.NoError(t, conn.Ping())
+
+	dbConf := config.DefaultDataBaseConfig()
+	dbConf.DBName = dbName
+	dbConf.AutoMigrate = false
+	obe := &db.Postgre{Config: &config.Config{DataBase: dbConf}, ObjDB: conn}
+	require.NoError(t, obe.Migrate())
+	return obe
+}
+
+func TestLivenessWindow(t *testing.T) {
+	window, err := livenessWindow(httptest.NewRequest("GET", "/api/v2/validators/liveness", nil))
+	require.NoError(t, err)
+	require.Equal(t, uint64(defaultLivenessWindow), window)
+
+	window, err = livenessWindow(httptest.NewRequest("GET", "/api/v2/validators/liveness?window=20", nil))
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), window)
+
+	window, err = livenessWindow(httptest.NewRequest("GET", "/api/v2/validators/liveness?window=100000", nil))
+	require.NoError(t, err)
+	require.Equal(t, uint64(maxLivenessWindow), window)
+
+	for _, query := range []string{"window=0", "window=-1", "window=x"} {
+		_, err = livenessWindow(httptest.NewRequest("GET", "/api/v2/validators/liveness?"+query, nil))
+		require.Error(t, err, query)
+	}
+}
+
+func TestGetValidatorsLiveness(t *testing.T) {
+	obe := connectTestDB(t, "rpc_liveness_test")
+	defer obe.Disconnect()
+	dbAdapter = obe
+	configuration = &config.Config{App: &config.AppConfig{TrackSignatures: true}}
+	defer func() { configuration = nil }()
+
+	require.NoError(t, obe.SaveGenesisValidators([]bc.Validator{{Address: "V1", Power: 10}, {Address: "V2", Power: 20}}))
+	require.NoError(t, obe.InsertCommitSignatures([]bc.BlockInfo{
+		{Height: 2, LastCommitSize: 2, LastCommitSigners: []string{"V1", "V2"}},
+		{Height: 3, LastCommitSize: 2, LastCommitSigners: []string{"V1"}},
+		{Height: 4, LastCommitSize: 2, LastCommitSigners: []string{"V1"}},
+	}))
+
+	type livenessResponse struct {
+		ErrorNumber int `json:"error"`
+		Result      struct {
+			Window            uint64
+			Validators        []db.ValidatorLiveness
+			SignaturesTracked bool `json:"signatures_tracked"`
+		} `json:"result"`
+	}
+	get := func(query string) livenessResponse {
+		w := httptest.NewRecorder()
+		getValidatorsLiveness(w, httptest.NewRequest("GET", "/api/v2/validators/liveness"+query, nil))
+		var res livenessResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+		return res
+	}
+
+	res := get("")
+	require.Equal(t, 0, res.ErrorNumber)
+	require.Equal(t, uint64(defaultLivenessWindow), res.Result.Window)
+	require.True(t, res.Result.SignaturesTracked)
+	require.Equal(t, []db.ValidatorLiveness{
+		{Address: "V2", SignedBlocks: 1, MissedBlocks: 2, LastSignedHeight: 1},
+		{Address: "V1", SignedBlocks: 3, MissedBlocks: 0, LastSignedHeight: 3},
+	}, res.Result.Validators)
+
+	//only last height that has signatures is in window
+	res = get("?window=1")
+	require.Equal(t, uint64(1), res.Result.Window)
+	require.Equal(t, []db.ValidatorLiveness{
+		{Address: "V2", SignedBlocks: 0, MissedBlocks: 1, LastSignedHeight: 0},
+		{Address: "V1", SignedBlocks: 1, MissedBlocks: 0, LastSignedHeight: 3},
+	}, res.Result.Validators)
+
+	require.Equal(t, 1, get("?window=0").ErrorNumber)
+}
+
+func TestSignaturesTracked(t *testing.T) {
+	configuration = nil
+	require.False(t, signaturesTracked())
+
+	configuration = &config.Config{App: config.DefaultAppConfig()}
+	defer func() { configuration = nil }()
+	require.False(t, signaturesTracked())
+
+	configuration.App.TrackSignatures = true
+	require.True(t, signaturesTracked())
+}
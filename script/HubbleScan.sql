@@ -1,243 +0,0 @@
---
--- PostgreSQL database dump
---
-
--- Dumped from database version 10.10 (Ubuntu 10.10-0ubuntu0.18.04.1)
--- Dumped by pg_dump version 10.10 (Ubuntu 10.10-0ubuntu0.18.04.1)
-
-SET statement_timeout = 0;
-SET lock_timeout = 0;
-SET idle_in_transaction_session_timeout = 0;
-SET client_encoding = 'UTF8';
-SET standard_conforming_strings = on;
-SELECT pg_catalog.set_config('search_path', '', false);
-SET check_function_bodies = false;
-SET xmloption = content;
-SET client_min_messages = warning;
-SET row_security = off;
-
-ALTER TABLE ONLY public.transactions DROP CONSTRAINT transactions_pkey;
-ALTER TABLE ONLY public.accounts DROP CONSTRAINT accounts_pkey;
-ALTER TABLE public.transactions ALTER COLUMN id DROP DEFAULT;
-ALTER TABLE public.accounts ALTER COLUMN id DROP DEFAULT;
-DROP TABLE public.useraccounts;
-DROP SEQUENCE public.useraccounts_id_seq;
-DROP SEQUENCE public.transactions_id_seq;
-DROP TABLE public.transactions;
-DROP TABLE public.blocks;
-DROP SEQUENCE public.blocks_id_seq;
-DROP SEQUENCE public.accounts_id_seq;
-DROP TABLE public.accounts;
-DROP EXTENSION plpgsql;
-DROP SCHEMA public;
---
--- Name: public; Type: SCHEMA; Schema: -; Owner: postgres
---
-
-CREATE SCHEMA public;
-
-
-ALTER SCHEMA public OWNER TO postgres;
-
---
--- Name: SCHEMA public; Type: COMMENT; Schema: -; Owner: postgres
---
-
-COMMENT ON SCHEMA public IS 'standard public schema';
-
-
---
--- Name: plpgsql; Type: EXTENSION; Schema: -; Owner: 
---
-
-CREATE EXTENSION IF NOT EXISTS plpgsql WITH SCHEMA pg_catalog;
-
-
---
--- Name: EXTENSION plpgsql; Type: COMMENT; Schema: -; Owner: 
---
-
-COMMENT ON EXTENSION plpgsql IS 'PL/pgSQL procedural language';
-
-
-SET default_tablespace = '';
-
-SET default_with_oids = false;
-
---
--- Name: accounts; Type: TABLE; Schema: public; Owner: postgres
---
-
-CREATE TABLE public.accounts (
-    id integer NOT NULL,
-    address character varying(256),
-    balance double precision,
-    permission character varying(256),
-    sequence character varying(256),
-    code character varying(256)
-);
-
-
-ALTER TABLE public.accounts OWNER TO postgres;
-
---
--- Name: accounts_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
---
-
-CREATE SEQUENCE public.accounts_id_seq
-    AS integer
-    START WITH 1
-    INCREMENT BY 1
-    NO MINVALUE
-    NO MAXVALUE
-    CACHE 1;
-
-
-ALTER TABLE public.accounts_id_seq OWNER TO postgres;
-
---
--- Name: accounts_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
---
-
-ALTER SEQUENCE public.accounts_id_seq OWNED BY public.accounts.id;
-
-
---
--- Name: blocks_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
---
-
-CREATE SEQUENCE public.blocks_id_seq
-    START WITH 1
-    INCREMENT BY 1
-    NO MINVALUE
-    NO MAXVALUE
-    CACHE 1;
-
-
-ALTER TABLE public.blocks_id_seq OWNER TO postgres;
-
---
--- Name: blocks; Type: TABLE; Schema: public; Owner: postgres
---
-
-CREATE TABLE public.blocks (
-    height bigint DEFAULT nextval('public.blocks_id_seq'::regclass),
-    hash character varying(256),
-    chainid text,
-    "time" timestamp without time zone,
-    txcounts bigint,
-    duration bigint DEFAULT 0
-);
-
-
-ALTER TABLE public.blocks OWNER TO postgres;
-
---
--- Name: transactions; Type: TABLE; Schema: public; Owner: postgres
---
-
-CREATE TABLE public.transactions (
-    id integer NOT NULL,
-    block_id integer,
-    txhash character varying(256),
-    fee bigint,
-    gas_limit bigint,
-    data character varying,
-    addr_from character varying(64),
-    addr_to character varying(64),
-    amount bigint,
-    tx_type character varying(10)
-);
-
-
-ALTER TABLE public.transactions OWNER TO postgres;
-
---
--- Name: transactions_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
---
-
-CREATE SEQUENCE public.transactions_id_seq
-    AS integer
-    START WITH 1
-    INCREMENT BY 1
-    NO MINVALUE
-    NO MAXVALUE
-    CACHE 1;
-
-
-ALTER TABLE public.transactions_id_seq OWNER TO postgres;
-
---
--- Name: transactions_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: postgres
---
-
-ALTER SEQUENCE public.transactions_id_seq OWNED BY public.transactions.id;
-
-
---
--- Name: useraccounts_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
---
-
-CREATE SEQUENCE public.useraccounts_id_seq
-    START WITH 1
-    INCREMENT BY 1
-    NO MINVALUE
-    NO MAXVALUE
-    CACHE 1;
-
-
-ALTER TABLE public.useraccounts_id_seq OWNER TO postgres;
-
---
--- Name: useraccounts; Type: TABLE; Schema: public; Owner: postgres
---
-
-CREATE TABLE public.useraccounts (
-    id bigint DEFAULT nextval('public.useraccounts_id_seq'::regclass) NOT NULL,
-    address character varying(256) NOT NULL,
-    num_txs bigint DEFAULT 0 NOT NULL
-);
-
-
-ALTER TABLE public.useraccounts OWNER TO postgres;
-
---
--- Name: accounts id; Type: DEFAULT; Schema: public; Owner: postgres
---
-
-ALTER TABLE ONLY public.accounts ALTER COLUMN id SET DEFAULT nextval('public.accounts_id_seq'::regclass);
-
-
---
--- Name: transactions id; Type: DEFAULT; Schema: public; Owner: postgres
---
-
-ALTER TABLE ONLY public.transactions ALTER COLUMN id SET DEFAULT nextval('public.transactions_id_seq'::regclass);
-
-
---
--- Name: accounts accounts_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
---
-
-ALTER TABLE ONLY public.accounts
-    ADD CONSTRAINT accounts_pkey PRIMARY KEY (id);
-
-
---
--- Name: transactions transactions_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
---
-
-ALTER TABLE ONLY public.transactions
-    ADD CONSTRAINT transactions_pkey PRIMARY KEY (id);
-
-
---
--- Name: SCHEMA public; Type: ACL; Schema: -; Owner: postgres
---
-
-GRANT ALL ON SCHEMA public TO PUBLIC;
-
-
---
--- PostgreSQL database dump complete
---